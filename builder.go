@@ -0,0 +1,112 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import "github.com/TimeWtr/logx/core"
+
+// ConfigBuilder 链式构造配置，是Options之外的可选方式，比如：
+//
+//	cfg, err := NewConfigBuilder().FilePath("./logs").Level(core.InfoLevel).Threshold(100 * 1024 * 1024).Build()
+//
+// ConfigBuilder是值类型，每个方法修改的是配置的副本并返回新的构造器，原来的构造器不受影响，
+// 可以作为公共的模板派生出多个配置
+type ConfigBuilder struct {
+	cfg Config
+}
+
+// NewConfigBuilder 使用NewConfig的默认配置创建构造器，日志文件的保存路径需要通过FilePath设置
+func NewConfigBuilder() ConfigBuilder {
+	return ConfigBuilder{cfg: *NewConfig("")}
+}
+
+// With 应用配置选项，用于构造器没有对应方法的选项
+func (b ConfigBuilder) With(opts ...Options) ConfigBuilder {
+	for _, opt := range opts {
+		opt(&b.cfg)
+	}
+
+	return b
+}
+
+// Build 校验配置，合法时返回配置的副本，错误与Config.Validate相同
+func (b ConfigBuilder) Build() (*Config, error) {
+	cfg := b.cfg
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// FilePath 设置日志文件的保存路径
+func (b ConfigBuilder) FilePath(filePath string) ConfigBuilder {
+	b.cfg.filePath = filePath
+	return b
+}
+
+// FileName 设置日志文件名称
+func (b ConfigBuilder) FileName(fileName string) ConfigBuilder {
+	return b.With(WithFileName(fileName))
+}
+
+// Level 设置日志级别
+func (b ConfigBuilder) Level(level core.LoggerLevel) ConfigBuilder {
+	return b.With(WithLevel(level))
+}
+
+// Color 开启日志输出颜色
+func (b ConfigBuilder) Color() ConfigBuilder {
+	return b.With(WithColor())
+}
+
+// Line 设置是否打印行号
+func (b ConfigBuilder) Line(enable bool) ConfigBuilder {
+	return b.With(WithLine(enable))
+}
+
+// CallSkip 设置异常级别打印的堆栈信息层级
+func (b ConfigBuilder) CallSkip(skip int) ConfigBuilder {
+	return b.With(WithCallSkip(skip))
+}
+
+// CallDepth 设置获取调用方时额外跳过的层级
+func (b ConfigBuilder) CallDepth(extraDepth int) ConfigBuilder {
+	return b.With(WithCallDepth(extraDepth))
+}
+
+// Location 设置时区
+func (b ConfigBuilder) Location(location string) ConfigBuilder {
+	return b.With(WithLocation(location))
+}
+
+// TimestampFormat 设置日志时间的格式
+func (b ConfigBuilder) TimestampFormat(layout string) ConfigBuilder {
+	return b.With(WithTimestampFormat(layout))
+}
+
+// Threshold 设置单个日志文件的大小阈值，单位bytes
+func (b ConfigBuilder) Threshold(threshold int64) ConfigBuilder {
+	return b.With(WithThreshold(threshold))
+}
+
+// Period 设置日志文件的保存周期，单位为天
+func (b ConfigBuilder) Period(period int) ConfigBuilder {
+	return b.With(WithPeriod(period))
+}
+
+// Compress 开启历史日志文件压缩，并设置压缩的级别
+func (b ConfigBuilder) Compress(level CompressLevel) ConfigBuilder {
+	return b.With(WithEnableCompress(), WithCompressionLevel(level))
+}
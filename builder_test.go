@@ -0,0 +1,71 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigBuilder(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := NewConfigBuilder().
+		FilePath(dir).
+		FileName("app.log").
+		Level(core.WarnLevel).
+		Line(false).
+		CallSkip(2).
+		Threshold(1024).
+		Period(7).
+		Compress(BestCompression).
+		With(WithUTC()).
+		Build()
+	assert.NoError(t, err)
+	assert.Equal(t, NewConfig(dir,
+		WithFileName("app.log"),
+		WithLevel(core.WarnLevel),
+		WithLine(false),
+		WithCallSkip(2),
+		WithThreshold(1024),
+		WithPeriod(7),
+		WithEnableCompress(),
+		WithCompressionLevel(BestCompression),
+		WithUTC()), cfg)
+
+	lg, err := NewLogWithConfig(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, core.WarnLevel, lg.GetLevel())
+	assert.NoError(t, lg.Close())
+
+	// 派生的构造器不会修改原来的构造器
+	base := NewConfigBuilder().FilePath(dir)
+	_ = base.Level(core.ErrorLevel).Threshold(-1)
+	cfg, err = base.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, core.InfoLevel, cfg.level)
+	assert.Equal(t, int64(DefaultLogSize), cfg.threshold)
+
+	// Build时校验配置
+	_, err = NewConfigBuilder().Build()
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+	assert.Contains(t, err.Error(), "file path")
+	_, err = base.Threshold(-1).Location("Mars/Base").Build()
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+	assert.Contains(t, err.Error(), "threshold")
+	assert.Contains(t, err.Error(), "location")
+}
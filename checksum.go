@@ -0,0 +1,137 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TimeWtr/logx/_const"
+	"github.com/TimeWtr/logx/errorx"
+)
+
+// ChecksumAlgo 历史日志文件的校验和算法，用于审计场景校验日志文件是否被篡改
+type ChecksumAlgo int
+
+const (
+	// ChecksumNone 不生成校验和文件，默认值
+	ChecksumNone ChecksumAlgo = iota
+	// SHA256 生成sha256sum兼容的.sha256校验和文件
+	SHA256
+	// SHA512 生成sha512sum兼容的.sha512校验和文件
+	SHA512
+)
+
+// checksumAlgos 查找校验和文件时支持的算法
+var checksumAlgos = []ChecksumAlgo{SHA256, SHA512}
+
+// valid 校验是否是合法的校验和算法
+func (a ChecksumAlgo) valid() bool {
+	return a >= ChecksumNone && a <= SHA512
+}
+
+// Ext 校验和文件的后缀
+func (a ChecksumAlgo) Ext() string {
+	switch a {
+	case SHA256:
+		return ".sha256"
+	case SHA512:
+		return ".sha512"
+	default:
+		return ""
+	}
+}
+
+func (a ChecksumAlgo) hash() hash.Hash {
+	switch a {
+	case SHA512:
+		return sha512.New()
+	default:
+		return sha256.New()
+	}
+}
+
+// WriteChecksum 计算文件的哈希值，写入同目录下的filename+Ext()校验和文件，内容为
+// sha256sum兼容的"<hex_hash>  <filename>"格式，返回校验和文件的路径
+func WriteChecksum(filename string, algo ChecksumAlgo) (string, error) {
+	if algo == ChecksumNone || !algo.valid() {
+		return "", &errorx.ConfigError{Field: "checksum algo", Reason: fmt.Sprintf("%d", algo)}
+	}
+
+	sum, err := fileChecksum(filename, algo)
+	if err != nil {
+		return "", err
+	}
+
+	path := filename + algo.Ext()
+	content := sum + "  " + filepath.Base(filename) + "\n"
+	if err = os.WriteFile(path, []byte(content), _const.ReadWriteFile); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// VerifyChecksum 依次查找filename对应的.sha256、.sha512校验和文件，校验文件的哈希值是否一致，
+// 只读取文件，可以在外部的归档进程中调用，校验和文件不存在时返回os.ErrNotExist
+func VerifyChecksum(filename string) (bool, error) {
+	for _, algo := range checksumAlgos {
+		data, err := os.ReadFile(filename + algo.Ext())
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+
+		want, _, ok := strings.Cut(string(data), "  ")
+		if !ok {
+			return false, fmt.Errorf("malformed checksum file %s", filename+algo.Ext())
+		}
+
+		sum, err := fileChecksum(filename, algo)
+		if err != nil {
+			return false, err
+		}
+
+		return strings.EqualFold(sum, want), nil
+	}
+
+	return false, fmt.Errorf("checksum file of %s: %w", filename, os.ErrNotExist)
+}
+
+// fileChecksum 计算文件十六进制格式的哈希值
+func fileChecksum(filename string, algo ChecksumAlgo) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := algo.hash()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
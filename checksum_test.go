@@ -0,0 +1,81 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "server.log.gz")
+	assert.NoError(t, os.WriteFile(filename, []byte("hello world"), 0o644))
+
+	_, err := VerifyChecksum(filename)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = WriteChecksum(filename, ChecksumNone)
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+
+	testCases := []struct {
+		name string
+		algo ChecksumAlgo
+		want string
+	}{
+		{
+			name: "SHA256",
+			algo: SHA256,
+			want: "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9  server.log.gz\n",
+		},
+		{
+			name: "SHA512",
+			algo: SHA512,
+			want: "309ecc489c12d6eb4cc40f50c902f2b4d0ed77ee511a7c7a9bcd3ca86d4cd86f" +
+				"989dd35bc5ff499670da34255b45b0cfd830e81f605dcf7dc5542e93ae9cd76f  server.log.gz\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := WriteChecksum(filename, tc.algo)
+			assert.NoError(t, err)
+			assert.Equal(t, filename+tc.algo.Ext(), path)
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(data))
+
+			ok, err := VerifyChecksum(filename)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.NoError(t, os.Remove(path))
+		})
+	}
+
+	// 文件被篡改后校验失败
+	_, err = WriteChecksum(filename, SHA256)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filename, []byte("hello logx"), 0o644))
+	ok, err := VerifyChecksum(filename)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, os.WriteFile(filename+SHA256.Ext(), []byte("garbage"), 0o644))
+	_, err = VerifyChecksum(filename)
+	assert.Error(t, err)
+}
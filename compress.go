@@ -0,0 +1,54 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import "compress/gzip"
+
+// CompressLevel 历史日志文件的压缩级别
+type CompressLevel int
+
+const (
+	// DefaultCompression 默认的压缩级别，兼顾速度和压缩率
+	DefaultCompression CompressLevel = iota
+	// NoCompression 不压缩，只做格式封装
+	NoCompression
+	// BestSpeed 压缩速度最快
+	BestSpeed
+	// BestCompression 压缩率最高
+	BestCompression
+	// HuffmanOnly 只使用霍夫曼编码
+	HuffmanOnly
+)
+
+// valid 校验是否是合法的压缩级别
+func (c CompressLevel) valid() bool {
+	return c >= DefaultCompression && c <= HuffmanOnly
+}
+
+// Int 压缩级别转换为gzip的压缩级别
+func (c CompressLevel) Int() int {
+	switch c {
+	case NoCompression:
+		return gzip.NoCompression
+	case BestSpeed:
+		return gzip.BestSpeed
+	case BestCompression:
+		return gzip.BestCompression
+	case HuffmanOnly:
+		return gzip.HuffmanOnly
+	default:
+		return gzip.DefaultCompression
+	}
+}
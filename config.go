@@ -14,7 +14,15 @@
 
 package logx
 
-import "github.com/TimeWtr/logx/core"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+)
 
 type Config struct {
 	// 日志文件的保存路径
@@ -27,12 +35,24 @@ type Config struct {
 	enableLine bool
 	// 是否开启颜色，默认关闭
 	enableColor bool
+	// 自定义日志级别的颜色
+	colorMap map[core.LoggerLevel]core.Colorizer
+	// 结构化日志的格式化器，为空时输出文本格式
+	formatter core.BinaryFormatter
 	// ErrorLevel、PanicLevel和FatalLevel级别下，堆栈追踪的行数，即追踪的调用级别，默认3级
 	callSkip int
+	// 获取调用方时额外跳过的层级，logx被其他库封装时跳过封装层，默认为0
+	callDepth int
 	// 是否开启异步写入
 	enableAsync bool
 	// 时区
 	location string
+	// 日志时间的格式，默认为time.RFC3339Nano
+	timeLayout string
+	// 是否使用UTC时间
+	enableUTC bool
+	// 是否去除时间中的单调时钟读数
+	stripMonotonic bool
 	// 单个日志文件阈值，允许保存多大的文件，单位bytes
 	threshold int64
 	// 日志文件的保存周期，单位为天，默认为30天
@@ -41,4 +61,100 @@ type Config struct {
 	enableCompress bool
 	// 压缩的级别
 	compressionLevel CompressLevel
+	// 按照日志级别额外写入的文件
+	levelFiles []levelFile
+}
+
+// levelFile level及以上级别的日志额外写入path指定的文件
+type levelFile struct {
+	level core.LoggerLevel
+	path  string
+}
+
+// levelFilePath 返回WithLevelFile配置的文件路径，相对路径相对于日志文件的保存路径
+func (c *Config) levelFilePath(lf levelFile) string {
+	if filepath.IsAbs(lf.path) {
+		return lf.path
+	}
+
+	return filepath.Join(c.filePath, lf.path)
+}
+
+// NewConfig 创建默认配置，并应用所有的配置选项，不做合法性校验
+func NewConfig(filePath string, opts ...Options) *Config {
+	cfg := &Config{
+		filePath:       filePath,
+		filename:       DefaultFilename,
+		level:          core.InfoLevel,
+		location:       DefaultLocation,
+		timeLayout:     time.RFC3339Nano,
+		enableLine:     true,
+		callSkip:       DefaultErrCoreSkip,
+		threshold:      DefaultLogSize,
+		period:         DefaultPeriod,
+		enableCompress: false,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// Validate 校验配置是否合法，会校验所有的配置项，并通过errors.Join聚合所有不合法的配置项，
+// 每个错误都是*errorx.ConfigError，可以通过errors.As获取不合法的配置项和原因
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(field, format string, v ...any) {
+		errs = append(errs, &errorx.ConfigError{Field: field, Reason: fmt.Sprintf(format, v...)})
+	}
+
+	if c.filePath == "" {
+		invalid("file path", "can't be empty")
+	}
+	if c.filename == "" {
+		invalid("filename", "can't be empty")
+	}
+	if !c.level.Valid() {
+		invalid("level", "%s", c.level)
+	}
+	if c.callSkip < 0 {
+		invalid("call skip", "must be non-negative, got %d", c.callSkip)
+	}
+	if c.callDepth < 0 {
+		invalid("call depth", "must be non-negative, got %d", c.callDepth)
+	}
+	if _, err := time.LoadLocation(c.location); err != nil {
+		invalid("location", "%q: %s", c.location, err)
+	}
+	if c.timeLayout == "" {
+		invalid("timestamp layout", "can't be empty")
+	}
+	if c.threshold <= 0 {
+		invalid("threshold", "must be positive, got %d", c.threshold)
+	}
+	if c.period <= 0 {
+		invalid("period", "must be positive, got %d", c.period)
+	}
+	if !c.compressionLevel.valid() {
+		invalid("compression level", "%d", c.compressionLevel)
+	}
+	paths := make(map[string]struct{}, len(c.levelFiles))
+	for _, lf := range c.levelFiles {
+		if !lf.level.Valid() {
+			invalid("level file", "level %s", lf.level)
+		}
+		if lf.path == "" {
+			invalid("level file", "path can't be empty")
+			continue
+		}
+		path := c.levelFilePath(lf)
+		if _, ok := paths[path]; ok {
+			invalid("level file", "duplicate path %q", path)
+		}
+		paths[path] = struct{}{}
+	}
+
+	return errors.Join(errs...)
 }
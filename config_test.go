@@ -0,0 +1,112 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filePath string
+		opts     []Options
+		wantErr  bool
+		contains []string
+	}{
+		{
+			name:     "默认配置",
+			filePath: t.TempDir(),
+			opts:     []Options{WithValidation()},
+		},
+		{
+			name:     "开启压缩",
+			filePath: t.TempDir(),
+			opts:     []Options{WithEnableCompress(), WithCompressionLevel(BestCompression)},
+		},
+		{
+			name:     "级别文件不合法",
+			filePath: t.TempDir(),
+			opts: []Options{
+				WithLevelFile(core.LoggerLevel(100), "a.log"),
+				WithLevelFile(core.ErrorLevel, ""),
+				WithLevelFile(core.ErrorLevel, "error.log"),
+				WithLevelFile(core.WarnLevel, "error.log"),
+			},
+			wantErr:  true,
+			contains: []string{"level file", "path can't be empty", "duplicate path"},
+		},
+		{
+			name:     "文件路径为空",
+			filePath: "",
+			wantErr:  true,
+			contains: []string{"file path"},
+		},
+		{
+			name:     "聚合多个不合法配置",
+			filePath: t.TempDir(),
+			opts: []Options{
+				WithThreshold(-1),
+				WithPeriod(0),
+				WithLevel(core.LoggerLevel(100)),
+				WithLocation("Mars/Base"),
+				WithCompressionLevel(CompressLevel(100)),
+				WithCallSkip(-1),
+				WithCallDepth(-1),
+			},
+			wantErr: true,
+			contains: []string{"threshold", "period", "level", "location", "compression level",
+				"call skip", "call depth"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewLog(tc.filePath, tc.opts...)
+			if !tc.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			for _, s := range tc.contains {
+				assert.Contains(t, err.Error(), s)
+			}
+		})
+	}
+
+	cfg := &Config{filePath: "./logs", filename: DefaultFilename, level: core.InfoLevel, location: DefaultLocation}
+	err := cfg.Validate()
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+	assert.Contains(t, err.Error(), "timestamp layout")
+
+	// 通过errors.As获取不合法的配置项和原因
+	var target *errorx.ConfigError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, "timestamp layout", target.Field)
+	assert.Equal(t, "can't be empty", target.Reason)
+	assert.Equal(t, "invalid config: timestamp layout: can't be empty", target.Error())
+
+	_, err = NewLog("")
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, "file path", target.Field)
+	assert.NotErrorIs(t, errors.New("invalid config"), errorx.ErrConfigInvalid)
+}
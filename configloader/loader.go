@@ -0,0 +1,127 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configloader 从YAML配置文件中加载logx的配置，独立的模块避免core用户引入yaml依赖
+package configloader
+
+import (
+	"os"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile YAML配置文件的格式，字段与logx.Config一一对应，未配置的字段使用默认值
+type ConfigFile struct {
+	// 日志文件的保存路径
+	FilePath string `yaml:"file_path"`
+	// 文件名称
+	Filename string `yaml:"filename"`
+	// 日志级别，支持大小写，比如info、WARN
+	Level core.LoggerLevel `yaml:"level"`
+	// 是否打印行号，默认打印
+	EnableLine *bool `yaml:"enable_line"`
+	// 是否开启颜色
+	EnableColor bool `yaml:"enable_color"`
+	// 堆栈追踪的调用级别
+	CallSkip *int `yaml:"call_skip"`
+	// 获取调用方时额外跳过的层级，logx被其他库封装时设置为封装的层数
+	CallDepth int `yaml:"call_depth"`
+	// 是否开启异步写入
+	EnableAsync bool `yaml:"enable_async"`
+	// 时区
+	Location string `yaml:"location"`
+	// 日志时间的格式
+	TimestampFormat string `yaml:"timestamp_format"`
+	// 是否使用UTC时间
+	UTC bool `yaml:"utc"`
+	// 单个日志文件阈值，单位bytes
+	Threshold int64 `yaml:"threshold"`
+	// 日志文件的保存周期，单位为天
+	Period int `yaml:"period"`
+	// 历史的日志文件是否开启压缩
+	EnableCompress bool `yaml:"enable_compress"`
+	// 压缩的级别
+	CompressionLevel logx.CompressLevel `yaml:"compression_level"`
+}
+
+// LoadConfigFromYAML 从YAML文件中加载配置，并校验配置是否合法
+func LoadConfigFromYAML(path string) (*logx.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cf ConfigFile
+	if err = yaml.Unmarshal(data, &cf); err != nil {
+		return nil, err
+	}
+
+	cfg := logx.NewConfig(cf.FilePath, cf.Options()...)
+	if err = cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Options 转换为logx的配置选项，零值字段不生成选项，保留logx的默认值
+func (cf *ConfigFile) Options() []logx.Options {
+	var opts []logx.Options
+	if cf.Filename != "" {
+		opts = append(opts, logx.WithFileName(cf.Filename))
+	}
+	if cf.Level != 0 {
+		opts = append(opts, logx.WithLevel(cf.Level))
+	}
+	if cf.EnableLine != nil {
+		opts = append(opts, logx.WithLine(*cf.EnableLine))
+	}
+	if cf.EnableColor {
+		opts = append(opts, logx.WithColor())
+	}
+	if cf.CallSkip != nil {
+		opts = append(opts, logx.WithCallSkip(*cf.CallSkip))
+	}
+	if cf.CallDepth != 0 {
+		opts = append(opts, logx.WithCallDepth(cf.CallDepth))
+	}
+	if cf.EnableAsync {
+		opts = append(opts, logx.WithAsync())
+	}
+	if cf.Location != "" {
+		opts = append(opts, logx.WithLocation(cf.Location))
+	}
+	if cf.TimestampFormat != "" {
+		opts = append(opts, logx.WithTimestampFormat(cf.TimestampFormat))
+	}
+	if cf.UTC {
+		opts = append(opts, logx.WithUTC())
+	}
+	if cf.Threshold != 0 {
+		opts = append(opts, logx.WithThreshold(cf.Threshold))
+	}
+	if cf.Period != 0 {
+		opts = append(opts, logx.WithPeriod(cf.Period))
+	}
+	if cf.EnableCompress {
+		opts = append(opts, logx.WithEnableCompress())
+	}
+	if cf.CompressionLevel != logx.DefaultCompression {
+		opts = append(opts, logx.WithCompressionLevel(cf.CompressionLevel))
+	}
+
+	return opts
+}
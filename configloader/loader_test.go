@@ -0,0 +1,87 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigFromYAML(t *testing.T) {
+	dir := t.TempDir()
+	testCases := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name: "合法配置",
+			content: `
+file_path: ` + filepath.Join(dir, "logs") + `
+filename: app.log
+level: WARN
+enable_line: false
+call_depth: 1
+threshold: 1048576
+period: 7
+enable_compress: true
+compression_level: 2
+`,
+		},
+		{
+			name: "不合法的日志级别",
+			content: `
+file_path: ./logs
+level: verbose
+`,
+			wantErr: errorx.ErrLevelInvalid,
+		},
+		{
+			name: "不合法的配置",
+			content: `
+file_path: ./logs
+period: -1
+call_depth: -1
+`,
+			wantErr: errorx.ErrConfigInvalid,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config"+string(rune('0'+i))+".yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tc.content), 0o644))
+
+			cfg, err := LoadConfigFromYAML(path)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			lg, err := logx.NewLogWithConfig(cfg)
+			assert.NoError(t, err)
+			assert.NotNil(t, lg)
+		})
+	}
+
+	_, err := LoadConfigFromYAML(filepath.Join(dir, "not_exist.yaml"))
+	assert.Error(t, err)
+}
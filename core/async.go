@@ -0,0 +1,201 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+)
+
+const (
+	// DefaultAsyncCapacity 异步写入队列的默认容量
+	DefaultAsyncCapacity = 1024
+	// DefaultAsyncFlushTimeout Flush等待队列写完的默认超时时间
+	DefaultAsyncFlushTimeout = 5 * time.Second
+)
+
+type AsyncWriterOptions func(*AsyncDecoratorWriter)
+
+// WithAsyncCapacity 设置异步写入队列的容量，小于1时使用1
+func WithAsyncCapacity(capacity int) AsyncWriterOptions {
+	return func(w *AsyncDecoratorWriter) {
+		w.capacity = max(capacity, 1)
+	}
+}
+
+// WithAsyncFlushTimeout 设置Flush等待队列写完的超时时间
+func WithAsyncFlushTimeout(timeout time.Duration) AsyncWriterOptions {
+	return func(w *AsyncDecoratorWriter) {
+		w.timeout = timeout
+	}
+}
+
+// asyncItem 异步写入队列中的数据，flush不为空时表示Flush的标记，写完之前的数据后刷新写入器
+type asyncItem struct {
+	p     []byte
+	e     *Entity
+	flush chan error
+}
+
+// AsyncDecoratorWriter 异步写入的装饰器，写入的数据先放入队列后立即返回，由后台协程按照顺序
+// 写入被装饰的写入器，避免HTTP、Kafka等慢速的写入器阻塞调用方。队列已满时丢弃日志，
+// 后台写入的错误输出到标准错误
+type AsyncDecoratorWriter struct {
+	// 被装饰的写入器
+	base Writer
+	// 队列的容量
+	capacity int
+	// Flush的超时时间
+	timeout time.Duration
+	// 异步写入队列
+	queue chan asyncItem
+	// 保护队列的关闭，写入时持有读锁，关闭时持有写锁，避免向已经关闭的队列写入
+	lock sync.RWMutex
+	// 是否已经关闭
+	closed bool
+	// 队列已满时丢弃的日志条数
+	dropped atomic.Int64
+	// 等待后台协程退出
+	wg sync.WaitGroup
+}
+
+// NewAsyncDecoratorWriter 创建异步写入器并启动后台写入协程
+func NewAsyncDecoratorWriter(base Writer, opts ...AsyncWriterOptions) *AsyncDecoratorWriter {
+	w := &AsyncDecoratorWriter{
+		base:     base,
+		capacity: DefaultAsyncCapacity,
+		timeout:  DefaultAsyncFlushTimeout,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.queue = make(chan asyncItem, w.capacity)
+
+	w.wg.Add(1)
+	go w.run()
+
+	return w
+}
+
+// Write 拷贝数据后放入队列立即返回，队列已满时丢弃并计数，关闭之后返回errorx.ErrBufferClose
+func (w *AsyncDecoratorWriter) Write(p []byte) (n int, err error) {
+	if err = w.enqueue(asyncItem{p: slices.Clone(p)}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteEntity 拷贝Entity后放入队列立即返回，调用方可以在返回之后把Entity放回对象池
+func (w *AsyncDecoratorWriter) WriteEntity(e Entity) error {
+	e.Fields = maps.Clone(e.Fields)
+	e.CE = slices.Clone(e.CE)
+
+	return w.enqueue(asyncItem{e: &e})
+}
+
+// Dropped 返回队列已满时丢弃的日志条数
+func (w *AsyncDecoratorWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Flush 等待队列中已有的日志全部写入后刷新被装饰的写入器，超时返回context.DeadlineExceeded
+func (w *AsyncDecoratorWriter) Flush() error {
+	w.lock.RLock()
+	if w.closed {
+		w.lock.RUnlock()
+		return errorx.ErrBufferClose
+	}
+
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	done := make(chan error, 1)
+	select {
+	case w.queue <- asyncItem{flush: done}:
+		w.lock.RUnlock()
+	case <-timer.C:
+		w.lock.RUnlock()
+		return context.DeadlineExceeded
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return context.DeadlineExceeded
+	}
+}
+
+// Close 停止接收日志，等待队列中的日志全部写入后关闭被装饰的写入器，重复调用直接返回nil
+func (w *AsyncDecoratorWriter) Close() error {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.lock.Unlock()
+
+	w.wg.Wait()
+	return w.base.Close()
+}
+
+func (w *AsyncDecoratorWriter) enqueue(item asyncItem) error {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.closed {
+		return errorx.ErrBufferClose
+	}
+
+	select {
+	case w.queue <- item:
+	default:
+		w.dropped.Add(1)
+	}
+
+	return nil
+}
+
+// run 按照顺序写入队列中的日志，队列关闭并且写完之后退出
+func (w *AsyncDecoratorWriter) run() {
+	defer w.wg.Done()
+
+	for item := range w.queue {
+		var err error
+		switch {
+		case item.flush != nil:
+			item.flush <- w.base.Flush()
+			continue
+		case item.e != nil:
+			err = w.base.WriteEntity(*item.e)
+		default:
+			_, err = w.base.Write(item.p)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logx: async write failed: %s\n", err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowWriter 每次写入之前等待gate，模拟慢速的网络写入器
+type slowWriter struct {
+	mockWriter
+	gate chan struct{}
+}
+
+func (s *slowWriter) Write(p []byte) (int, error) {
+	<-s.gate
+	return s.mockWriter.Write(p)
+}
+
+func (s *slowWriter) WriteEntity(e Entity) error {
+	<-s.gate
+	return s.mockWriter.WriteEntity(e)
+}
+
+func TestAsyncDecoratorWriter(t *testing.T) {
+	base := &slowWriter{gate: make(chan struct{})}
+	w := NewAsyncDecoratorWriter(base, WithAsyncCapacity(2), WithAsyncFlushTimeout(50*time.Millisecond))
+
+	// 后台协程取出第一条后阻塞在写入，队列中还可以放两条
+	n, err := w.Write([]byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Eventually(t, func() bool {
+		return len(w.queue) == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, w.WriteEntity(Entity{Message: "b", Fields: map[string]any{"k": "v"}}))
+	_, err = w.Write([]byte("c"))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("d"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), w.Dropped())
+
+	// 被装饰的写入器阻塞时Flush超时
+	assert.ErrorIs(t, w.Flush(), context.DeadlineExceeded)
+
+	close(base.gate)
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, "abc", base.buf.String())
+
+	_, err = w.Write([]byte("e"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+	assert.Equal(t, "abce", base.buf.String())
+	assert.True(t, base.closed)
+
+	_, err = w.Write([]byte("f"))
+	assert.Equal(t, errorx.ErrBufferClose, err)
+	assert.Equal(t, errorx.ErrBufferClose, w.WriteEntity(Entity{}))
+	assert.Equal(t, errorx.ErrBufferClose, w.Flush())
+}
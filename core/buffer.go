@@ -15,7 +15,7 @@
 package core
 
 import (
-	"fmt"
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -30,8 +30,44 @@ const (
 	PercentThreshold = 0.8
 	// TimeThreshold 缓冲区切换的时间阈值
 	TimeThreshold = 1 * time.Second
+	// DefaultCloseTimeout Close等待后台协程退出的默认超时时间
+	DefaultCloseTimeout = 5 * time.Second
+	// DefaultFlushTimeout Flush等待异步读取器转移日志的默认超时时间
+	DefaultFlushTimeout = 5 * time.Second
 )
 
+// BufferStats 缓冲区的健康状态，用于健康检查和监控接口
+type BufferStats struct {
+	// 还没有被消费的日志条数
+	Backlog int
+	// 活跃缓冲区、备用缓冲区和readq的总容量
+	Capacity int
+	// 正在转移日志的异步读取器数量
+	Readers int32
+	// 积压是否超过总容量的80%
+	Overload bool
+	// 估算的缓冲区中日志占用的内存字节数
+	MemoryUsage uint64
+	// 超过内存限制被丢弃的日志条数
+	MemoryDrops int64
+}
+
+// Statser 提供缓冲区和对象池健康状态的组件
+type Statser interface {
+	BufferStats() BufferStats
+	PoolStats() PoolStats
+}
+
+type BufferOptions func(*Buffer)
+
+// WithMemoryLimit 设置缓冲区中日志占用内存的上限，超过上限时Write直接返回ErrBufferFull，
+// 避免日志风暴时活跃缓冲区和readq堆积大量的日志导致OOM，为0时不限制
+func WithMemoryLimit(maxBytes uint64) BufferOptions {
+	return func(b *Buffer) {
+		b.memLimit = maxBytes
+	}
+}
+
 // Buffer 缓冲区包含两个缓冲通道，active缓冲区为活跃缓冲区，实时接收日志数据
 // passive缓冲区为备用缓冲区，当active缓冲区达到阈值/定时，进行缓冲通道的切换，passive缓冲区
 // 切换为活跃缓冲区，开始实时接收日志数据，原来的active缓冲区切换为异步刷盘缓冲区，异步从缓冲区中读取
@@ -47,23 +83,40 @@ type Buffer struct {
 	passive chan string
 	// 异步读取通道
 	readq chan string
-	// 关闭缓冲区的信号
+	// 关闭缓冲区的信号，停止写入和定时切换
 	sig chan struct{}
+	// 关闭超时的信号，异步读取器放弃还没有转移的日志
+	abort chan struct{}
+	// 缓冲区切换的事件通知，提供给下游的消费者
+	events chan struct{}
+	// 缓冲区切换的内部通知，用于asyncWork重置定时切换
+	switched chan struct{}
 	// 单例
 	once sync.Once
-	// 活跃缓冲区写入的字节大小
-	size uint64
+	// 活跃缓冲区写入的字节大小，原子操作，内存限制的检查不需要加锁
+	size atomic.Uint64
+	// 已经切换出去、还没有转移到readq中的日志条数
+	moving atomic.Int64
+	// 累计写入的字节数和条数，用于估算正在转移和readq中日志的平均长度
+	written atomic.Uint64
+	writes  atomic.Uint64
+	// 内存限制，为0时不限制
+	memLimit uint64
+	// 超过内存限制被丢弃的日志条数
+	memoryDrops atomic.Int64
 	// 加锁保护
 	lock sync.Mutex
 	// 异步刷盘的goroutine数量
 	counter atomic.Int32
+	// 等待定时切换的asyncWork协程退出
+	wg sync.WaitGroup
 	// 对象池
 	pool *WrapPool[chan string]
 }
 
 // NewBuffer 双缓冲通道设计，capacity为单个缓冲通道的容量，maxSize为对象池中
 // 允许创建的最大对象数量
-func NewBuffer(capacity int64, maxSize int) (*Buffer, error) {
+func NewBuffer(capacity int64, maxSize int, opts ...BufferOptions) (*Buffer, error) {
 	pool, err := NewWrapPool[chan string](func() chan string {
 		return make(chan string, capacity)
 	}, func(ch chan string) chan string {
@@ -92,15 +145,22 @@ func NewBuffer(capacity int64, maxSize int) (*Buffer, error) {
 
 	const bufferMultiplier = 2
 	b := &Buffer{
-		active:  active,
-		passive: passive,
-		sig:     make(chan struct{}),
-		readq:   make(chan string, capacity*bufferMultiplier),
-		lock:    sync.Mutex{},
-		pool:    pool,
+		active:   active,
+		passive:  passive,
+		sig:      make(chan struct{}),
+		abort:    make(chan struct{}),
+		events:   make(chan struct{}, 1),
+		switched: make(chan struct{}, 1),
+		readq:    make(chan string, capacity*bufferMultiplier),
+		lock:     sync.Mutex{},
+		pool:     pool,
 	}
 	b.counter.Store(0)
+	for _, opt := range opts {
+		opt(b)
+	}
 
+	b.wg.Add(1)
 	go b.asyncWork()
 
 	return b, nil
@@ -113,10 +173,16 @@ func (b *Buffer) Write(p string) error {
 	default:
 	}
 
+	pSize := len(p)
+	// 加锁之前检查内存限制，日志风暴时快速失败，不和正常的写入竞争锁
+	if b.memLimit > 0 && b.MemoryUsage()+uint64(pSize) > b.memLimit {
+		b.memoryDrops.Add(1)
+		return ex.ErrBufferFull
+	}
+
 	b.lock.Lock()
 	defer b.lock.Unlock()
-	pSize := len(p)
-	if b.size+uint64(pSize) > SizeThreshold || float64(len(b.active)) >= float64(cap(b.active))*PercentThreshold {
+	if b.size.Load()+uint64(pSize) > SizeThreshold || float64(len(b.active)) >= float64(cap(b.active))*PercentThreshold {
 		// 执行切换逻辑
 		b.sw()
 	}
@@ -125,7 +191,9 @@ func (b *Buffer) Write(p string) error {
 	case <-b.sig:
 		return ex.ErrBufferClose
 	case b.active <- p:
-		b.size += uint64(pSize)
+		b.size.Add(uint64(pSize))
+		b.written.Add(uint64(pSize))
+		b.writes.Add(1)
 		return nil
 	default:
 		return ex.ErrBufferFull
@@ -139,37 +207,69 @@ func (b *Buffer) Register() <-chan string {
 	return b.readq
 }
 
-// sw 执行切换逻辑
+// sw 执行切换逻辑，对象池中的缓冲通道全部被占用时等待其他通道归还
 func (b *Buffer) sw() {
+	b.swap(b.pool.Get)
+}
+
+// trySw 非阻塞的执行切换逻辑，对象池中的缓冲通道全部被占用时不切换，返回是否切换成功
+func (b *Buffer) trySw() bool {
+	return b.swap(b.pool.TryGet)
+}
+
+// swap 先获取新的缓冲通道再把活跃缓冲区交给异步读取器，获取失败时不切换，
+// 避免活跃缓冲区在被读取器转移、放回对象池的同时继续被写入
+func (b *Buffer) swap(get func() (chan string, error)) bool {
+	select {
+	case <-b.sig:
+		return false
+	default:
+	}
+
+	newBuf, err := get()
+	if err != nil {
+		return false
+	}
+
 	active := b.active
+	b.moving.Add(int64(len(active)))
 	b.counter.Add(1)
 	go b.asyncReader(active)
 
-	for {
-		select {
-		case <-b.sig:
-			return
-		default:
-			newBuf, err := b.pool.Get()
-			if err != nil {
-				return
-			}
-			b.active, b.passive = b.passive, newBuf
-			b.size = 0
-			return
-		}
+	b.active, b.passive = b.passive, newBuf
+	b.size.Store(0)
+	notify(b.events)
+	notify(b.switched)
+	return true
+}
+
+// notify 非阻塞的发送切换通知，通道中已有未消费的通知时直接丢弃，不会阻塞切换
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
 	}
 }
 
+// SwitchEvents 返回缓冲区切换的事件通道，每次切换之后发送一个通知，通道的容量为1，
+// 消费者处理不及时时多次切换只会保留一个通知，所有的调用方共享同一个通道
+func (b *Buffer) SwitchEvents() <-chan struct{} {
+	return b.events
+}
+
+// asyncWork 定时切换缓冲区，因为写入达到阈值或者Flush已经切换时重新计时，避免刚切换完又立即切换
 func (b *Buffer) asyncWork() {
+	defer b.wg.Done()
 	ticker := time.NewTicker(TimeThreshold)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
 		select {
 		case <-b.sig:
 			return
-		default:
+		case <-b.switched:
+			ticker.Reset(TimeThreshold)
+		case <-ticker.C:
 			b.lock.Lock()
 			b.sw()
 			b.lock.Unlock()
@@ -177,9 +277,12 @@ func (b *Buffer) asyncWork() {
 	}
 }
 
-// asyncReader 异步读取器，后台异步的把缓冲通道中的日志数据读取出来，并写入到readq中
+// asyncReader 异步读取器，后台异步的把缓冲通道中的日志数据读取出来，并写入到readq中，
+// 关闭缓冲区时继续转移剩余的日志，保证readq关闭之前收到所有已写入的日志，只有关闭超时才放弃
 func (b *Buffer) asyncReader(ch chan string) {
 	defer func() {
+		// 关闭时没有转移的日志会被丢弃，不再计入内存占用
+		b.moving.Add(-int64(len(ch)))
 		b.counter.Add(-1)
 		b.pool.Put(ch)
 	}()
@@ -187,34 +290,186 @@ func (b *Buffer) asyncReader(ch chan string) {
 	// 读取缓冲区中所有的数据，直到为空退出
 	for len(ch) > 0 {
 		select {
-		case <-b.sig:
+		case <-b.abort:
 			return
 		case data := <-ch:
+			b.moving.Add(-1)
 			select {
-			case <-b.sig:
+			case <-b.abort:
 				return
 			case b.readq <- data:
 			}
 		}
 	}
-	fmt.Println("读取完成，退出")
 }
 
+// Backlog 返回还没有被消费的日志条数，包括活跃缓冲区、备用缓冲区和readq中的日志，
+// 通道长度的读取本身是原子的，但是切换缓冲区时会修改active和passive，所以需要加锁
+func (b *Buffer) Backlog() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.active) + len(b.passive) + len(b.readq)
+}
+
+// Overload 积压的日志超过总容量的80%时返回true，说明消费的速度跟不上写入的速度
+func (b *Buffer) Overload() bool {
+	b.lock.Lock()
+	capacity := cap(b.active) + cap(b.passive) + cap(b.readq)
+	b.lock.Unlock()
+
+	return float64(b.Backlog()) > float64(capacity)*PercentThreshold
+}
+
+// BufferStats 返回缓冲区的积压和容量信息
+func (b *Buffer) BufferStats() BufferStats {
+	b.lock.Lock()
+	backlog := len(b.active) + len(b.passive) + len(b.readq)
+	capacity := cap(b.active) + cap(b.passive) + cap(b.readq)
+	b.lock.Unlock()
+
+	return BufferStats{
+		Backlog:     backlog,
+		Capacity:    capacity,
+		Readers:     b.counter.Load(),
+		Overload:    float64(backlog) > float64(capacity)*PercentThreshold,
+		MemoryUsage: b.MemoryUsage(),
+		MemoryDrops: b.memoryDrops.Load(),
+	}
+}
+
+// MemoryUsage 估算缓冲区中日志占用的内存字节数，活跃缓冲区按照实际写入的字节数计算，
+// 正在转移和readq中的日志按照累计写入的平均长度估算，readq在创建后不会修改，不需要加锁
+func (b *Buffer) MemoryUsage() uint64 {
+	usage := b.size.Load()
+	writes := b.writes.Load()
+	if writes == 0 {
+		return usage
+	}
+
+	pending := uint64(max(b.moving.Load(), 0)) + uint64(len(b.readq))
+	return usage + pending*(b.written.Load()/writes)
+}
+
+// PoolStats 返回缓冲通道对象池的统计信息
+func (b *Buffer) PoolStats() PoolStats {
+	return b.pool.PoolStats()
+}
+
+// Flush 使用默认的超时时间刷新缓冲区，参考FlushWithTimeout
+func (b *Buffer) Flush() error {
+	return b.FlushWithTimeout(DefaultFlushTimeout)
+}
+
+// FlushWithTimeout 主动切换缓冲通道，并等待异步读取器把已写入的日志数据全部转移到readq中，
+// 与Close不同，Flush之后缓冲区仍然可以继续写入。没有可用的缓冲通道或者消费者处理不及时导致超时时返回
+// context.DeadlineExceeded，异步读取器继续在后台转移日志，期间缓冲区被关闭时返回ErrBufferClose
+func (b *Buffer) FlushWithTimeout(timeout time.Duration) error {
+	select {
+	case <-b.sig:
+		return ex.ErrBufferClose
+	default:
+	}
+
+	const sleepInterval = time.Millisecond * 5
+	ticker := time.NewTicker(sleepInterval)
+	defer ticker.Stop()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// 先开始计时再切换，对象池中的缓冲通道全部被占用时等待归还，超时后放弃切换
+	for {
+		b.lock.Lock()
+		switched := b.trySw()
+		b.lock.Unlock()
+		if switched {
+			break
+		}
+
+		select {
+		case <-b.sig:
+			return ex.ErrBufferClose
+		case <-timer.C:
+			return context.DeadlineExceeded
+		case <-ticker.C:
+		}
+	}
+
+	for b.counter.Load() > 0 {
+		select {
+		case <-b.sig:
+			return ex.ErrBufferClose
+		case <-timer.C:
+			return context.DeadlineExceeded
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// Close 使用默认的超时时间关闭缓冲区，参考CloseWithTimeout
 func (b *Buffer) Close() {
+	_ = b.CloseWithTimeout(DefaultCloseTimeout)
+}
+
+// CloseWithTimeout 按照顺序关闭缓冲区：停止写入和定时切换，等待异步读取器把已经切换出去的日志
+// 全部转移到readq中，再把活跃缓冲区中剩余的日志转移到readq中，最后关闭readq。消费者处理不及时
+// 导致超时时，通知异步读取器放弃剩余的日志，仍然尽力转移活跃缓冲区中的日志，但是不关闭readq，
+// 避免仍在退出的异步读取器向已关闭的通道写入，并返回context.DeadlineExceeded。重复调用直接返回nil
+func (b *Buffer) CloseWithTimeout(timeout time.Duration) error {
+	var err error
 	b.once.Do(func() {
 		close(b.sig)
-		close(b.active)
-		close(b.passive)
-		
-		b.counter.Add(1)
-		b.asyncReader(b.active)
-		const sleepInterval = time.Millisecond * 5
-		for b.counter.Load() > 0 {
-			time.Sleep(sleepInterval)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			b.wg.Wait()
+			const sleepInterval = time.Millisecond * 5
+			for b.counter.Load() > 0 {
+				time.Sleep(sleepInterval)
+			}
+		}()
+
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			close(b.abort)
+			err = context.DeadlineExceeded
 		}
-		close(b.readq)
 
+		b.drain()
+		if err == nil {
+			close(b.readq)
+		}
+	})
+
+	return err
+}
+
+// drain 把活跃缓冲区中剩余的日志转移到readq中，readq已满时丢弃剩余的日志，
+// 完成后把缓冲通道放回对象池
+func (b *Buffer) drain() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	defer func() {
 		b.pool.Put(b.active)
 		b.pool.Put(b.passive)
-	})
+	}()
+	for {
+		select {
+		case data := <-b.active:
+			select {
+			case b.readq <- data:
+			default:
+				return
+			}
+		default:
+			return
+		}
+	}
 }
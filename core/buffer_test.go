@@ -15,12 +15,16 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/TimeWtr/logx/errorx"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,6 +73,82 @@ func TestNewBuffer(t *testing.T) {
 	t.Log("写入成功")
 }
 
+func TestBuffer_Flush(t *testing.T) {
+	bf, err := NewBuffer(100, 10)
+	assert.NoError(t, err)
+	ch := bf.Register()
+
+	for round := 0; round < 2; round++ {
+		for i := 0; i < 10; i++ {
+			assert.NoError(t, bf.Write(strconv.Itoa(i)))
+		}
+		// Flush之后数据全部转移到readq中，缓冲区仍然可以继续写入
+		assert.NoError(t, bf.Flush())
+		assert.Len(t, ch, 10)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, strconv.Itoa(i), <-ch)
+		}
+	}
+}
+
+func TestBuffer_FlushWithTimeout(t *testing.T) {
+	bf, err := NewBuffer(10, 10)
+	assert.NoError(t, err)
+	defer bf.CloseWithTimeout(10 * time.Millisecond)
+
+	// readq已满并且没有消费者时，异步读取器无法完成转移，Flush超时返回
+	for i := 0; i < 30; i++ {
+		_ = bf.Write(strconv.Itoa(i))
+	}
+	start := time.Now()
+	assert.ErrorIs(t, bf.FlushWithTimeout(50*time.Millisecond), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestBuffer_FlushWithTimeout_PoolExhausted(t *testing.T) {
+	// 对象池中只有活跃和备用两个缓冲通道，Flush无法获取新的通道，超时后放弃切换
+	bf, err := NewBuffer(10, 2)
+	assert.NoError(t, err)
+	defer bf.CloseWithTimeout(10 * time.Millisecond)
+
+	assert.NoError(t, bf.Write("a"))
+	start := time.Now()
+	assert.ErrorIs(t, bf.FlushWithTimeout(50*time.Millisecond), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, bf.Backlog())
+}
+
+func TestBuffer_Backlog(t *testing.T) {
+	bf, err := NewBuffer(10, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, bf.Backlog())
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, bf.Write(strconv.Itoa(i)))
+	}
+	assert.Equal(t, 5, bf.Backlog())
+	// Flush之后日志转移到readq中，仍然没有被消费
+	assert.NoError(t, bf.Flush())
+	assert.Equal(t, 5, bf.Backlog())
+	assert.False(t, bf.Overload())
+
+	// 总容量为10+10+20，积压超过80%时过载
+	bf.lock.Lock()
+	for len(bf.readq) < cap(bf.readq) {
+		bf.readq <- "readq"
+	}
+	for len(bf.passive) < cap(bf.passive) {
+		bf.passive <- "passive"
+	}
+	bf.lock.Unlock()
+	assert.Equal(t, 30, bf.Backlog())
+	assert.False(t, bf.Overload())
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, bf.Write("active"))
+	}
+	assert.True(t, bf.Overload())
+}
+
 func BenchmarkNewBuffer(b *testing.B) {
 	bf, err := NewBuffer(5000, 10)
 	assert.NoError(b, err)
@@ -165,3 +245,197 @@ func BenchmarkNewBuffer_No_Log(b *testing.B) {
 	wg.Wait()
 	b.Log("写入成功")
 }
+
+func TestBuffer_SwitchEvents(t *testing.T) {
+	bf, err := NewBuffer(10, 10)
+	assert.NoError(t, err)
+	events := bf.SwitchEvents()
+	assert.Equal(t, events, bf.SwitchEvents())
+
+	// 多次切换只会保留一个通知，切换不会被阻塞
+	assert.NoError(t, bf.Write("a"))
+	assert.NoError(t, bf.Flush())
+	assert.NoError(t, bf.Flush())
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		t.Fatal("switch event not received")
+	}
+	assert.Len(t, events, 0)
+
+	// 写入达到阈值时触发切换
+	for i := 0; i < 9; i++ {
+		assert.NoError(t, bf.Write(strconv.Itoa(i)))
+	}
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		t.Fatal("switch event not received")
+	}
+}
+
+func TestBuffer_CloseWithTimeout(t *testing.T) {
+	bf, err := NewBuffer(10, 10)
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, bf.Write(strconv.Itoa(i)))
+	}
+
+	assert.NoError(t, bf.CloseWithTimeout(time.Second))
+	assert.NoError(t, bf.CloseWithTimeout(time.Second))
+	assert.ErrorIs(t, bf.Write("closed"), errorx.ErrBufferClose)
+
+	// 关闭前写入的日志全部转移到readq中，之后readq被关闭
+	var got []string
+	for data := range bf.Register() {
+		got = append(got, data)
+	}
+	assert.Equal(t, []string{"0", "1", "2", "3", "4"}, got)
+
+	// 后台协程没有在超时时间内退出时返回超时错误，并且不关闭readq
+	bf, err = NewBuffer(10, 10)
+	assert.NoError(t, err)
+	assert.NoError(t, bf.Write("pending"))
+	bf.wg.Add(1)
+	defer bf.wg.Done()
+	assert.ErrorIs(t, bf.CloseWithTimeout(10*time.Millisecond), context.DeadlineExceeded)
+	select {
+	case data, ok := <-bf.Register():
+		assert.True(t, ok)
+		assert.Equal(t, "pending", data)
+	default:
+		t.Fatal("pending data not drained")
+	}
+}
+
+func TestBuffer_ConcurrentWriteFlush(t *testing.T) {
+	bf, err := NewBuffer(100, 10)
+	assert.NoError(t, err)
+
+	// 先注册消费者，写入期间读取器不会因为readq已满而阻塞
+	ch := bf.Register()
+	var received atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ch {
+			received.Add(1)
+		}
+	}()
+
+	const writers, flushers, perWriter = 8, 4, 500
+	var written atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				// 缓冲区已满时丢弃，只统计写入成功的日志
+				if bf.Write(strconv.Itoa(j)) == nil {
+					written.Add(1)
+				}
+			}
+		}()
+	}
+	for i := 0; i < flushers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, bf.Flush())
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Flush等待异步读取器完成转移，写入成功的日志全部被消费
+	assert.NoError(t, bf.Flush())
+	assert.NoError(t, bf.CloseWithTimeout(time.Second))
+	<-done
+	assert.Equal(t, written.Load(), received.Load())
+	assert.Positive(t, written.Load())
+}
+
+func TestBuffer_Stats(t *testing.T) {
+	bf, err := NewBuffer(10, 10)
+	assert.NoError(t, err)
+	var s Statser = bf
+
+	assert.NoError(t, bf.Write("a"))
+	assert.NoError(t, bf.Write("b"))
+	stats := s.BufferStats()
+	assert.Equal(t, 2, stats.Backlog)
+	assert.Equal(t, 40, stats.Capacity)
+	assert.False(t, stats.Overload)
+	assert.Equal(t, bf.Backlog(), stats.Backlog)
+
+	ps := s.PoolStats()
+	assert.Equal(t, int64(10), ps.MaxSize)
+	// 两个缓冲通道都从预先生成的对象中获取
+	assert.Equal(t, int64(0), ps.Allocations)
+	assert.Equal(t, int64(2), ps.Reuses)
+	assert.Equal(t, int64(1), ps.CurrentCount)
+}
+
+func TestBuffer_MemoryLimit(t *testing.T) {
+	bf, err := NewBuffer(100, 10, WithMemoryLimit(100))
+	assert.NoError(t, err)
+	defer bf.Close()
+
+	entry := strings.Repeat("x", 10)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, bf.Write(entry))
+	}
+	assert.Equal(t, uint64(100), bf.MemoryUsage())
+	assert.Equal(t, errorx.ErrBufferFull, bf.Write(entry))
+
+	// 转移到readq中的日志按照平均长度估算，仍然计入内存占用
+	assert.NoError(t, bf.Flush())
+	assert.Equal(t, uint64(100), bf.MemoryUsage())
+	assert.Equal(t, errorx.ErrBufferFull, bf.Write(entry))
+
+	ch := bf.Register()
+	for i := 0; i < 5; i++ {
+		<-ch
+	}
+	assert.Equal(t, uint64(50), bf.MemoryUsage())
+	assert.NoError(t, bf.Write(entry))
+
+	stats := bf.BufferStats()
+	assert.Equal(t, int64(2), stats.MemoryDrops)
+	assert.Equal(t, uint64(60), stats.MemoryUsage)
+}
+
+func TestCloseWithPendingData(t *testing.T) {
+	bf, err := NewBuffer(20, 10)
+	assert.NoError(t, err)
+
+	ch := bf.Register()
+	received := make(map[string]bool)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for data := range ch {
+			received[data] = true
+		}
+	}()
+
+	// 写入期间频繁切换缓冲区，关闭时仍有异步读取器在转移日志
+	const total = 2000
+	written := make([]string, 0, total)
+	for i := 0; i < total; i++ {
+		data := strconv.Itoa(i)
+		if bf.Write(data) == nil {
+			written = append(written, data)
+		}
+	}
+	assert.NotEmpty(t, written)
+	assert.NoError(t, bf.CloseWithTimeout(time.Second))
+	<-done
+
+	assert.Len(t, received, len(written))
+	for _, data := range written {
+		assert.True(t, received[data], "missing %s", data)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+)
+
+// circuitEventsSize 熔断器状态变更事件通道的容量，通道满时丢弃事件，避免阻塞写入
+const circuitEventsSize = 16
+
+// CircuitState 熔断器的状态
+type CircuitState uint8
+
+const (
+	// CircuitClosed 关闭状态，正常写入
+	CircuitClosed CircuitState = iota
+	// CircuitHalfOpen 半开状态，只允许一次探测写入
+	CircuitHalfOpen
+	// CircuitOpen 打开状态，直接返回errorx.ErrCircuitOpen
+	CircuitOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitHalfOpen:
+		return "half-open"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitEvent 熔断器的状态变更事件
+type CircuitEvent struct {
+	// 变更前的状态
+	From CircuitState
+	// 变更后的状态
+	To CircuitState
+	// 变更的时间
+	Time time.Time
+}
+
+// CircuitBreakerWriter 熔断器装饰器，连续写入失败达到阈值后打开熔断器，写入直接返回
+// errorx.ErrCircuitOpen，避免网络写入器不可用时增加业务的延迟。经过resetTimeout后进入
+// 半开状态，允许一次探测写入，成功则关闭熔断器，失败则重新打开并重新计时
+type CircuitBreakerWriter struct {
+	// 被装饰的写入器
+	base Writer
+	// 打开熔断器的连续失败次数
+	threshold int
+	// 打开状态到半开状态的时间
+	resetTimeout time.Duration
+	// 并发保护
+	lock sync.Mutex
+	// 当前的状态
+	state CircuitState
+	// 连续失败的次数
+	failures int
+	// 熔断器打开的时间
+	openedAt time.Time
+	// 半开状态下是否有正在执行的探测写入
+	probing bool
+	// 状态变更事件
+	events chan CircuitEvent
+}
+
+// NewCircuitBreakerWriter 创建熔断器写入器，threshold小于1时按照1处理
+func NewCircuitBreakerWriter(base Writer, threshold int, resetTimeout time.Duration) *CircuitBreakerWriter {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreakerWriter{
+		base:         base,
+		threshold:    threshold,
+		resetTimeout: resetTimeout,
+		events:       make(chan CircuitEvent, circuitEventsSize),
+	}
+}
+
+func (c *CircuitBreakerWriter) Write(p []byte) (n int, err error) {
+	if err = c.acquire(); err != nil {
+		return 0, err
+	}
+
+	n, err = c.base.Write(p)
+	c.release(err)
+	return n, err
+}
+
+func (c *CircuitBreakerWriter) WriteEntity(e Entity) error {
+	if err := c.acquire(); err != nil {
+		return err
+	}
+
+	err := c.base.WriteEntity(e)
+	c.release(err)
+	return err
+}
+
+// Flush 直接刷新，不受熔断器状态的影响
+func (c *CircuitBreakerWriter) Flush() error {
+	return c.base.Flush()
+}
+
+func (c *CircuitBreakerWriter) Close() error {
+	return c.base.Close()
+}
+
+// State 返回熔断器当前的状态，用于监控
+func (c *CircuitBreakerWriter) State() CircuitState {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.state
+}
+
+// Events 返回状态变更事件的通道，没有及时读取的事件会被丢弃
+func (c *CircuitBreakerWriter) Events() <-chan CircuitEvent {
+	return c.events
+}
+
+// acquire 校验是否允许写入，打开状态超过resetTimeout时进入半开状态并允许一次探测写入
+func (c *CircuitBreakerWriter) acquire() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch c.state {
+	case CircuitOpen:
+		if time.Since(c.openedAt) < c.resetTimeout {
+			return errorx.ErrCircuitOpen
+		}
+		c.transition(CircuitHalfOpen)
+	case CircuitHalfOpen:
+		if c.probing {
+			return errorx.ErrCircuitOpen
+		}
+	default:
+		return nil
+	}
+
+	c.probing = true
+	return nil
+}
+
+// release 根据写入的结果更新熔断器的状态
+func (c *CircuitBreakerWriter) release(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.probing = false
+	if err == nil {
+		c.failures = 0
+		if c.state != CircuitClosed {
+			c.transition(CircuitClosed)
+		}
+		return
+	}
+
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= c.threshold {
+		c.openedAt = time.Now()
+		if c.state != CircuitOpen {
+			c.transition(CircuitOpen)
+		}
+	}
+}
+
+// transition 变更状态并发送事件，调用方需要持有锁
+func (c *CircuitBreakerWriter) transition(to CircuitState) {
+	event := CircuitEvent{
+		From: c.state,
+		To:   to,
+		Time: time.Now(),
+	}
+	c.state = to
+
+	select {
+	case c.events <- event:
+	default:
+	}
+}
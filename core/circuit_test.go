@@ -0,0 +1,82 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreakerWriter(t *testing.T) {
+	const resetTimeout = 20 * time.Millisecond
+	mockErr := errors.New("mock error")
+	base := &mockWriter{fails: 5, err: mockErr}
+	w := NewCircuitBreakerWriter(base, 2, resetTimeout)
+	assert.Equal(t, CircuitClosed, w.State())
+
+	// 连续失败达到阈值后打开熔断器，不再执行真正的写入
+	for i := 0; i < 2; i++ {
+		_, err := w.Write([]byte("a"))
+		assert.ErrorIs(t, err, mockErr)
+	}
+	assert.Equal(t, CircuitOpen, w.State())
+	_, err := w.Write([]byte("a"))
+	assert.ErrorIs(t, err, errorx.ErrCircuitOpen)
+	assert.Equal(t, 2, base.writes)
+
+	// 半开状态下探测失败，重新打开熔断器
+	time.Sleep(resetTimeout)
+	_, err = w.Write([]byte("a"))
+	assert.ErrorIs(t, err, mockErr)
+	assert.Equal(t, CircuitOpen, w.State())
+	_, err = w.Write([]byte("a"))
+	assert.ErrorIs(t, err, errorx.ErrCircuitOpen)
+
+	// 半开状态下探测成功，关闭熔断器
+	base.fails = 0
+	time.Sleep(resetTimeout)
+	n, err := w.Write([]byte("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, CircuitClosed, w.State())
+
+	expected := []CircuitEvent{
+		{From: CircuitClosed, To: CircuitOpen},
+		{From: CircuitOpen, To: CircuitHalfOpen},
+		{From: CircuitHalfOpen, To: CircuitOpen},
+		{From: CircuitOpen, To: CircuitHalfOpen},
+		{From: CircuitHalfOpen, To: CircuitClosed},
+	}
+	for _, want := range expected {
+		event := <-w.Events()
+		assert.Equal(t, want.From, event.From)
+		assert.Equal(t, want.To, event.To)
+	}
+	assert.Empty(t, w.Events())
+
+	assert.NoError(t, w.Close())
+	assert.True(t, base.closed)
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "unknown", CircuitState(10).String())
+}
@@ -16,6 +16,9 @@ package core
 
 import (
 	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -25,41 +28,178 @@ const (
 	ErrorColor
 	PanicColor
 	FatalColor
+
+	// NoticeColor 青色，区分于InfoLevel和WarnLevel
+	NoticeColor Color = 36
+	// TraceColor 亮黑色(灰色)，弱化显示最详细的追踪日志
+	TraceColor Color = 90
+)
+
+// levelColors 自定义日志级别的颜色注册表
+var (
+	levelColors   = make(map[LoggerLevel]Color)
+	levelColorsMu sync.RWMutex
 )
 
+// RegisterLevelColor 注册自定义日志级别对应的颜色，通常和RegisterLevel配合使用，重复注册时
+// 后者覆盖前者。内置级别的颜色不会被修改，需要通过WithLevelColors自定义
+func RegisterLevelColor(level LoggerLevel, color Color) {
+	if level.builtin() {
+		return
+	}
+
+	levelColorsMu.Lock()
+	defer levelColorsMu.Unlock()
+	levelColors[level] = color
+}
+
+// Color 返回日志级别对应的颜色，没有注册颜色的自定义级别返回0
+func (l LoggerLevel) Color() Color {
+	switch l {
+	case TraceLevel:
+		return TraceColor
+	case DebugLevel:
+		return DebugColor
+	case InfoLevel:
+		return InfoColor
+	case NoticeLevel:
+		return NoticeColor
+	case WarnLevel:
+		return WarnColor
+	case ErrorLevel:
+		return ErrorColor
+	case PanicLevel:
+		return PanicColor
+	case FatalLevel:
+		return FatalColor
+	default:
+	}
+
+	levelColorsMu.RLock()
+	defer levelColorsMu.RUnlock()
+	return levelColors[l]
+}
+
+// Colorizer 颜色抽象，为日志级别的前缀加上颜色的转义序列
+type Colorizer interface {
+	String(s string) string
+}
+
+// Color 标准的16色ANSI颜色(30-37/90-97)
 type Color uint8
 
 func (c Color) String(s string) string {
 	return fmt.Sprintf("\x1b[1;%dm[%s] \x1b[0m", uint8(c), s)
 }
 
+// Color256 256色ANSI颜色，Color256(n)即可构造对应的颜色
+type Color256 uint8
+
+func (c Color256) String(s string) string {
+	return fmt.Sprintf("\x1b[1;38;5;%dm[%s] \x1b[0m", uint8(c), s)
+}
+
+// RGBColor 24位真彩色
+type RGBColor struct {
+	R, G, B uint8
+}
+
+// ColorRGB 构造24位真彩色
+func ColorRGB(r, g, b uint8) RGBColor {
+	return RGBColor{R: r, G: g, B: b}
+}
+
+func (c RGBColor) String(s string) string {
+	return fmt.Sprintf("\x1b[1;38;2;%d;%d;%dm[%s] \x1b[0m", c.R, c.G, c.B, s)
+}
+
 // ColorPlugin 日志颜色插件
 type ColorPlugin interface {
 	Format(enabled bool, level LoggerLevel) string
 }
 
-type ANSIColorPlugin struct{}
+// NoColorPlugin 无颜色插件，无论是否开启颜色都只输出[LEVEL]前缀，
+// 适用于写入文件、CI日志等非终端场景以及测试
+type NoColorPlugin struct{}
+
+func NewNoColorPlugin() ColorPlugin {
+	return NoColorPlugin{}
+}
+
+func (NoColorPlugin) Format(_ bool, level LoggerLevel) string {
+	return "[" + level.UpperString() + "] "
+}
+
+type ANSIColorPluginOption func(*ANSIColorPlugin)
 
-func NewANSIColorPlugin() ColorPlugin {
-	return &ANSIColorPlugin{}
+// WithForceColor 强制开启颜色输出，忽略终端检测和NO_COLOR环境变量，
+// 适用于支持ANSI颜色但标准输出不是终端的CI环境
+func WithForceColor() ANSIColorPluginOption {
+	return func(p *ANSIColorPlugin) {
+		p.colorable.Store(true)
+	}
+}
+
+// WithLevelColors 自定义日志级别对应的颜色，支持Color、Color256和RGBColor，
+// 未设置的日志级别使用默认颜色
+func WithLevelColors(m map[LoggerLevel]Colorizer) ANSIColorPluginOption {
+	return func(p *ANSIColorPlugin) {
+		for level, c := range m {
+			if c != nil {
+				p.colors[level] = c
+			}
+		}
+	}
+}
+
+type ANSIColorPlugin struct {
+	// 是否支持颜色输出，构造时检测一次并缓存结果，避免每次Format都检测文件描述符
+	colorable atomic.Bool
+	// 自定义的日志级别颜色，构造完成后只读
+	colors map[LoggerLevel]Colorizer
+}
+
+func NewANSIColorPlugin(opts ...ANSIColorPluginOption) ColorPlugin {
+	p := &ANSIColorPlugin{
+		colors: make(map[LoggerLevel]Colorizer),
+	}
+	p.colorable.Store(supportColor())
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	// Windows控制台需要先开启虚拟终端处理，失败时即使强制开启颜色也只能输出无颜色格式
+	if p.colorable.Load() && !enableVirtualTerminal() {
+		p.colorable.Store(false)
+	}
+
+	return p
+}
+
+// supportColor 检测标准输出是否支持颜色，设置了NO_COLOR环境变量(https://no-color.org)
+// 或者标准输出被重定向到文件、管道等非终端设备时不支持颜色
+func supportColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
 }
 
 func (p *ANSIColorPlugin) Format(enabled bool, level LoggerLevel) string {
-	if enabled {
-		switch level {
-		case DebugLevel:
-			return DebugColor.String(level.UpperString())
-		case InfoLevel:
-			return InfoColor.String(level.UpperString())
-		case WarnLevel:
-			return WarnColor.String(level.UpperString())
-		case ErrorLevel:
-			return ErrorColor.String(level.UpperString())
-		case PanicLevel:
-			return PanicColor.String(level.UpperString())
-		case FatalLevel:
-			return FatalColor.String(level.UpperString())
-		default:
+	if enabled && p.colorable.Load() {
+		if c, ok := p.colors[level]; ok {
+			return c.String(level.UpperString())
+		}
+
+		if c := level.Color(); c != 0 {
+			return c.String(level.UpperString())
 		}
 	}
 
@@ -15,7 +15,10 @@
 package core
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewANSIColorPlugin(t *testing.T) {
@@ -30,3 +33,69 @@ func TestNewANSIColorPlugin(t *testing.T) {
 		cp.Format(true, level)
 	}
 }
+
+func TestANSIColorPlugin_Detect(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	cp := NewANSIColorPlugin()
+	for level := _minLevel; level <= _maxLevel; level++ {
+		assert.Equal(t, "["+level.UpperString()+"] ", cp.Format(true, level))
+	}
+
+	cp = NewANSIColorPlugin(WithForceColor())
+	for level := _minLevel; level <= _maxLevel; level++ {
+		assert.True(t, strings.HasPrefix(cp.Format(true, level), "\x1b["))
+		assert.Equal(t, "["+level.UpperString()+"] ", cp.Format(false, level))
+	}
+}
+
+func TestANSIColorPlugin_RichColor(t *testing.T) {
+	cp := NewANSIColorPlugin(WithForceColor(), WithLevelColors(map[LoggerLevel]Colorizer{
+		DebugLevel: Color256(208),
+		InfoLevel:  ColorRGB(255, 128, 0),
+		WarnLevel:  Color(93),
+	}))
+
+	assert.Equal(t, "\x1b[1;38;5;208m[DEBUG] \x1b[0m", cp.Format(true, DebugLevel))
+	assert.Equal(t, "\x1b[1;38;2;255;128;0m[INFO] \x1b[0m", cp.Format(true, InfoLevel))
+	assert.Equal(t, "\x1b[1;93m[WARN] \x1b[0m", cp.Format(true, WarnLevel))
+	assert.Equal(t, ErrorColor.String("ERROR"), cp.Format(true, ErrorLevel))
+	assert.Equal(t, "\x1b[1;90m[TRACE] \x1b[0m", cp.Format(true, TraceLevel))
+	assert.Equal(t, "[INFO] ", cp.Format(false, InfoLevel))
+}
+
+func TestLoggerLevel_Color(t *testing.T) {
+	assert.Equal(t, TraceColor, TraceLevel.Color())
+	assert.Equal(t, DebugColor, DebugLevel.Color())
+	assert.Equal(t, FatalColor, FatalLevel.Color())
+
+	const auditLevel LoggerLevel = 11
+	t.Cleanup(func() {
+		levelRegistryMu.Lock()
+		delete(levelRegistry, auditLevel)
+		levelRegistryMu.Unlock()
+		levelColorsMu.Lock()
+		delete(levelColors, auditLevel)
+		levelColorsMu.Unlock()
+	})
+	assert.NoError(t, RegisterLevel("audit", auditLevel))
+	assert.Equal(t, Color(0), auditLevel.Color())
+
+	cp := NewANSIColorPlugin(WithForceColor())
+	// 没有注册颜色的自定义级别输出无颜色格式
+	assert.Equal(t, "[AUDIT] ", cp.Format(true, auditLevel))
+
+	RegisterLevelColor(auditLevel, Color(96))
+	// 内置级别的颜色不会被修改
+	RegisterLevelColor(InfoLevel, Color(96))
+	assert.Equal(t, Color(96), auditLevel.Color())
+	assert.Equal(t, InfoColor, InfoLevel.Color())
+	assert.Equal(t, "\x1b[1;96m[AUDIT] \x1b[0m", cp.Format(true, auditLevel))
+	assert.Equal(t, InfoColor.String("INFO"), cp.Format(true, InfoLevel))
+}
+
+func TestNoColorPlugin(t *testing.T) {
+	cp := NewNoColorPlugin()
+	assert.Equal(t, "[INFO] ", cp.Format(true, InfoLevel))
+	assert.Equal(t, "[ERROR] ", cp.Format(false, ErrorLevel))
+	assert.Equal(t, "[TRACE] ", cp.Format(true, TraceLevel))
+}
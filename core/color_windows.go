@@ -0,0 +1,43 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package core
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// enableVirtualTerminal 为标准输出的控制台句柄开启ENABLE_VIRTUAL_TERMINAL_PROCESSING，
+// 开启后cmd.exe等控制台才能正确解析ANSI转义序列，旧版本的Windows不支持时返回false
+func enableVirtualTerminal() bool {
+	handle := windows.Handle(windows.Stdout)
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return false
+	}
+
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
+
+// NewWindowsColorPlugin Windows平台的颜色插件，开启控制台的虚拟终端处理，
+// 开启失败时退化为无颜色的[LEVEL]格式
+func NewWindowsColorPlugin(opts ...ANSIColorPluginOption) ColorPlugin {
+	return NewANSIColorPlugin(opts...)
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+// FilterWriter 按照日志级别过滤的装饰器，只有[minLevel, maxLevel]之间的日志才会写入，
+// 比如只把ErrorLevel及以上的日志发送到告警服务
+type FilterWriter struct {
+	// 被装饰的写入器
+	base Writer
+	// 允许写入的最低日志级别
+	minLevel LoggerLevel
+	// 允许写入的最高日志级别
+	maxLevel LoggerLevel
+}
+
+func NewFilterWriter(base Writer, minLevel, maxLevel LoggerLevel) *FilterWriter {
+	return &FilterWriter{
+		base:     base,
+		minLevel: minLevel,
+		maxLevel: maxLevel,
+	}
+}
+
+// Write 无法获取日志级别，直接写入不做过滤
+func (f *FilterWriter) Write(p []byte) (n int, err error) {
+	return f.base.Write(p)
+}
+
+// WriteEntity 不在日志级别范围内的日志直接丢弃，不返回错误
+func (f *FilterWriter) WriteEntity(e Entity) error {
+	if e.Level < f.minLevel || e.Level > f.maxLevel {
+		return nil
+	}
+
+	return f.base.WriteEntity(e)
+}
+
+func (f *FilterWriter) Flush() error {
+	return f.base.Flush()
+}
+
+func (f *FilterWriter) Close() error {
+	return f.base.Close()
+}
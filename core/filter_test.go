@@ -0,0 +1,40 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterWriter(t *testing.T) {
+	base := &mockWriter{}
+	w := NewFilterWriter(base, ErrorLevel, PanicLevel)
+
+	for _, level := range AllLevels() {
+		assert.NoError(t, w.WriteEntity(Entity{Level: level, Message: level.String() + ";"}))
+	}
+	assert.Equal(t, "error;panic;", base.buf.String())
+
+	// 字节数据无法获取日志级别，直接写入
+	_, err := w.Write([]byte("debug;"))
+	assert.NoError(t, err)
+	assert.Equal(t, "error;panic;debug;", base.buf.String())
+
+	assert.NoError(t, w.Flush())
+	assert.NoError(t, w.Close())
+	assert.True(t, base.closed)
+}
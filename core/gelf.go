@@ -0,0 +1,224 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/TimeWtr/logx/errorx"
+)
+
+const (
+	// GELFVersion GELF协议版本
+	GELFVersion = "1.1"
+	// GELFChunkSize UDP模式下单个数据包的最大大小，超过后需要分块发送
+	GELFChunkSize = 8192
+	// gelfChunkHeaderSize 分块头部的大小：魔数(2) + 消息ID(8) + 序号(1) + 总数(1)
+	gelfChunkHeaderSize = 12
+	// gelfMaxChunks GELF协议允许的最大分块数量
+	gelfMaxChunks = 128
+)
+
+// gelfMagic GELF分块数据包的魔数
+var gelfMagic = []byte{0x1e, 0x0f}
+
+// SyslogSeverity 日志级别转换为syslog的数值级别(RFC 5424)
+func SyslogSeverity(level LoggerLevel) int {
+	switch level {
+	case TraceLevel, DebugLevel:
+		return 7
+	case InfoLevel:
+		return 6
+	case NoticeLevel:
+		return 5
+	case WarnLevel:
+		return 4
+	case ErrorLevel:
+		return 3
+	case PanicLevel:
+		return 2
+	case FatalLevel:
+		return 1
+	default:
+		return 6
+	}
+}
+
+// GELFFormatter GELF 1.1(Graylog Extended Log Format)格式的格式化器
+type GELFFormatter struct {
+	// 主机名称
+	host string
+}
+
+func NewGELFFormatter() BinaryFormatter {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+
+	return &GELFFormatter{host: host}
+}
+
+func (f *GELFFormatter) Format(e Entity) []byte {
+	msg := make(map[string]any, len(e.Fields)+8)
+	for key, val := range e.Fields {
+		// GELF协议中_id是保留字段，不允许使用
+		if key == "id" {
+			key = "id_"
+		}
+		msg["_"+key] = val
+	}
+	if e.TraceID != "" {
+		msg["_trace_id"] = e.TraceID
+	}
+	if e.Service != "" {
+		msg["_service"] = e.Service
+	}
+
+	msg["version"] = GELFVersion
+	msg["host"] = f.host
+	msg["short_message"] = e.Message
+	// 时间戳为Unix秒，保留毫秒精度的小数
+	const msPerSecond = 1000
+	msg["timestamp"] = math.Round(float64(e.Timestamp)/1e6) / msPerSecond
+	msg["level"] = SyslogSeverity(e.Level)
+	if len(e.CE) > 0 {
+		frames := make([]string, 0, len(e.CE))
+		for _, ce := range e.CE {
+			data, _ := ce.MarshalJSON()
+			frames = append(frames, string(data))
+		}
+		msg["full_message"] = e.Message + "\n" + strings.Join(frames, "\n")
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// GELFWriter 发送GELF数据到Graylog，支持UDP和TCP协议，UDP模式下超过8192字节的消息
+// 会按照GELF协议分块发送，TCP模式下每条消息以空字节结尾
+type GELFWriter struct {
+	// 网络连接
+	conn net.Conn
+	// 是否是UDP协议
+	udp bool
+	// WriteEntity使用的GELF格式化器
+	formatter BinaryFormatter
+	// 保证单条消息的分块连续发送
+	lock sync.Mutex
+}
+
+// NewGELFWriter 创建GELF写入器，addr格式为udp://host:port或者tcp://host:port，
+// 不指定协议时默认使用UDP
+func NewGELFWriter(addr string) (Writer, error) {
+	network := "udp"
+	if idx := strings.Index(addr, "://"); idx >= 0 {
+		network, addr = addr[:idx], addr[idx+len("://"):]
+	}
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("unsupported gelf network: %s", network)
+	}
+
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GELFWriter{
+		conn:      conn,
+		udp:       network == "udp",
+		formatter: NewGELFFormatter(),
+	}, nil
+}
+
+func (g *GELFWriter) Write(p []byte) (n int, err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if !g.udp {
+		// TCP模式下使用空字节作为消息的分隔符
+		buf := make([]byte, len(p)+1)
+		copy(buf, p)
+		if _, err = g.conn.Write(buf); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	if len(p) <= GELFChunkSize {
+		return g.conn.Write(p)
+	}
+
+	return g.writeChunks(p)
+}
+
+// WriteEntity 使用GELF格式化器格式化后写入
+func (g *GELFWriter) WriteEntity(e Entity) error {
+	_, err := g.Write(g.formatter.Format(e))
+	return err
+}
+
+// writeChunks UDP模式下分块发送消息，每个分块都包含魔数、消息ID、分块序号和分块总数
+func (g *GELFWriter) writeChunks(p []byte) (int, error) {
+	const dataSize = GELFChunkSize - gelfChunkHeaderSize
+	total := (len(p) + dataSize - 1) / dataSize
+	if total > gelfMaxChunks {
+		return 0, fmt.Errorf("%w: %d bytes need %d chunks", errorx.ErrMessageTooLarge, len(p), total)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 0, GELFChunkSize)
+	for seq := 0; seq < total; seq++ {
+		end := (seq + 1) * dataSize
+		if end > len(p) {
+			end = len(p)
+		}
+
+		buf = buf[:0]
+		buf = append(buf, gelfMagic...)
+		buf = append(buf, id...)
+		buf = append(buf, byte(seq), byte(total))
+		buf = append(buf, p[seq*dataSize:end]...)
+		if _, err := g.conn.Write(buf); err != nil {
+			return seq * dataSize, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush 数据都是直接发送的，没有缓冲区需要刷新
+func (g *GELFWriter) Flush() error {
+	return nil
+}
+
+func (g *GELFWriter) Close() error {
+	return g.conn.Close()
+}
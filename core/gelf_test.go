@@ -0,0 +1,140 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGELFFormatter_Format(t *testing.T) {
+	f := NewGELFFormatter()
+	data := f.Format(Entity{
+		Timestamp: time.Date(2025, 5, 12, 12, 12, 0, 123456789, time.UTC).UnixNano(),
+		Level:     ErrorLevel,
+		Message:   "hello",
+		TraceID:   "trace-1",
+		Fields:    map[string]any{"uid": 1001, "id": "x"},
+		CE:        []CallerEntity{{file: "/src/foo/bar.go", line: 42, ok: true}},
+	})
+
+	var res map[string]any
+	assert.NoError(t, json.Unmarshal(data, &res))
+	assert.Equal(t, GELFVersion, res["version"])
+	assert.NotEmpty(t, res["host"])
+	assert.Equal(t, "hello", res["short_message"])
+	assert.Contains(t, res["full_message"], "bar.go")
+	assert.InDelta(t, 1747051920.123, res["timestamp"], 1e-6)
+	assert.Equal(t, float64(3), res["level"])
+	assert.Equal(t, float64(1001), res["_uid"])
+	assert.Equal(t, "x", res["_id_"])
+	assert.Equal(t, "trace-1", res["_trace_id"])
+	assert.NotContains(t, res, "_id")
+}
+
+func TestGELFWriter_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	w, err := NewGELFWriter("udp://" + conn.LocalAddr().String())
+	assert.NoError(t, err)
+	defer w.Close()
+
+	readPacket := func() []byte {
+		buf := make([]byte, GELFChunkSize*2)
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		return buf[:n]
+	}
+
+	// 小消息不分块
+	_, err = w.Write([]byte(`{"short_message":"hello"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"short_message":"hello"}`, string(readPacket()))
+
+	// 结构化日志使用GELF格式化器格式化
+	assert.NoError(t, w.WriteEntity(Entity{Level: ErrorLevel, Message: "hello"}))
+	var msg map[string]any
+	assert.NoError(t, json.Unmarshal(readPacket(), &msg))
+	assert.Equal(t, "hello", msg["short_message"])
+	assert.Equal(t, float64(3), msg["level"])
+
+	// 大消息分块发送
+	payload := []byte(strings.Repeat("a", GELFChunkSize*2+100))
+	n, err := w.Write(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, len(payload), n)
+
+	var res []byte
+	var id []byte
+	for i := 0; i < 3; i++ {
+		packet := readPacket()
+		assert.LessOrEqual(t, len(packet), GELFChunkSize)
+		assert.Equal(t, gelfMagic, packet[:2])
+		if id == nil {
+			id = packet[2:10]
+		}
+		assert.Equal(t, id, packet[2:10])
+		assert.Equal(t, byte(i), packet[10])
+		assert.Equal(t, byte(3), packet[11])
+		res = append(res, packet[gelfChunkHeaderSize:]...)
+	}
+	assert.Equal(t, payload, res)
+	assert.NoError(t, w.Flush())
+}
+
+func TestGELFWriter_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer ln.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		data, _ := bufio.NewReader(conn).ReadBytes(0)
+		received <- data
+	}()
+
+	w, err := NewGELFWriter("tcp://" + ln.Addr().String())
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte(`{"short_message":"hello"}`))
+	assert.NoError(t, err)
+	select {
+	case data := <-received:
+		assert.Equal(t, []byte("{\"short_message\":\"hello\"}\x00"), data)
+		assert.True(t, bytes.HasSuffix(data, []byte{0}))
+	case <-time.After(time.Second):
+		t.Fatal("receive timeout")
+	}
+
+	_, err = NewGELFWriter("http://" + ln.Addr().String())
+	assert.Error(t, err)
+}
@@ -16,15 +16,27 @@ package core
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/TimeWtr/logx/errorx"
 )
 
+// LoggerLevel 日志级别，数值越大越严重。内置级别的数值不保证跨版本稳定：加入TraceLevel后，
+// DebugLevel到FatalLevel的数值都加了1，加入NoticeLevel后，WarnLevel到FatalLevel的数值又加了1，
+// 持久化或者跨进程传递日志级别时使用级别名称(MarshalText/UnmarshalText)，不要保存数值
 type LoggerLevel uint8
 
 const (
+	// TraceLevel 比DebugLevel更详细的日志级别，用于追踪状态机、网络协议等细节，
+	// 需要通过WithLevel(TraceLevel)显式开启
+	TraceLevel LoggerLevel = iota + 1
 	// DebugLevel 用于开发环境调试的日志级别，生产环境中需要切换其他的级别
-	DebugLevel LoggerLevel = iota + 1
+	DebugLevel
 	// InfoLevel 默认的日志级别
 	InfoLevel
+	// NoticeLevel 正常但是值得关注的事件，对应syslog的NOTICE级别，介于InfoLevel和WarnLevel之间
+	NoticeLevel
 	// WarnLevel 出现了危险的情况需要打印日志，存在危险，但不影响系统的正常运行
 	WarnLevel
 	// ErrorLevel 比WarnLevel更严重，业务出现了明显的错误，系统仍可正常运行
@@ -34,17 +46,83 @@ const (
 	// FatalLevel 记录日志后，直接调用os.Exit(1)
 	FatalLevel
 
-	_minLevel = DebugLevel
+	_minLevel = TraceLevel
 	_maxLevel = FatalLevel
 )
 
+// AllLevels 返回所有的内置日志级别
+func AllLevels() []LoggerLevel {
+	levels := make([]LoggerLevel, 0, _maxLevel-_minLevel+1)
+	for level := _minLevel; level <= _maxLevel; level++ {
+		levels = append(levels, level)
+	}
+
+	return levels
+}
+
+// levelRegistry 用户自定义日志级别的注册表，key为日志级别，value为小写格式的级别名称
+var (
+	levelRegistry   = make(map[LoggerLevel]string)
+	levelRegistryMu sync.RWMutex
+)
+
+// RegisterLevel 注册自定义的日志级别，比如高于FatalLevel的AUDIT，自定义级别同样遵循
+// Enabled的比较语义，所以需要谨慎选择级别的数值，级别数值或者名称与内置级别、已注册
+// 级别冲突时返回错误
+func RegisterLevel(name string, value LoggerLevel) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return fmt.Errorf("%w: empty level name", errorx.ErrLevelInvalid)
+	}
+
+	if value.builtin() {
+		return fmt.Errorf("%w: value %d is a built-in level", errorx.ErrLevelConflict, value)
+	}
+
+	if l, ok := builtinLevel(name); ok {
+		return fmt.Errorf("%w: name %q is already used by level %d", errorx.ErrLevelConflict, name, l)
+	}
+
+	// 名称和数值的冲突检查与写入在同一个写锁内完成，避免并发注册同名的级别
+	levelRegistryMu.Lock()
+	defer levelRegistryMu.Unlock()
+
+	if l, ok := lookupRegistered(name); ok {
+		return fmt.Errorf("%w: name %q is already used by level %d", errorx.ErrLevelConflict, name, l)
+	}
+	if _, ok := levelRegistry[value]; ok {
+		return fmt.Errorf("%w: value %d is already registered", errorx.ErrLevelConflict, value)
+	}
+	levelRegistry[value] = name
+
+	return nil
+}
+
+// registered 查询自定义日志级别的名称
+func (l LoggerLevel) registered() (string, bool) {
+	levelRegistryMu.RLock()
+	defer levelRegistryMu.RUnlock()
+
+	name, ok := levelRegistry[l]
+	return name, ok
+}
+
+// builtin 是否是内置的日志级别
+func (l LoggerLevel) builtin() bool {
+	return l <= _maxLevel && l >= _minLevel
+}
+
 // String 用于校验并返回日志级别的小写格式的字符串内容
 func (l LoggerLevel) String() string {
 	switch l {
+	case TraceLevel:
+		return "trace"
 	case DebugLevel:
 		return "debug"
 	case InfoLevel:
 		return "info"
+	case NoticeLevel:
+		return "notice"
 	case WarnLevel:
 		return "warn"
 	case ErrorLevel:
@@ -54,6 +132,9 @@ func (l LoggerLevel) String() string {
 	case FatalLevel:
 		return "fatal"
 	default:
+		if name, ok := l.registered(); ok {
+			return name
+		}
 		return fmt.Sprintf("unknown level(%d)", l)
 	}
 }
@@ -61,10 +142,14 @@ func (l LoggerLevel) String() string {
 // UpperString 用于校验并返回日志级别大写格式的字符串内容
 func (l LoggerLevel) UpperString() string {
 	switch l {
+	case TraceLevel:
+		return "TRACE"
 	case DebugLevel:
 		return "DEBUG"
 	case InfoLevel:
 		return "INFO"
+	case NoticeLevel:
+		return "NOTICE"
 	case WarnLevel:
 		return "WARN"
 	case ErrorLevel:
@@ -74,19 +159,103 @@ func (l LoggerLevel) UpperString() string {
 	case FatalLevel:
 		return "FATAL"
 	default:
+		if name, ok := l.registered(); ok {
+			return strings.ToUpper(name)
+		}
 		return fmt.Sprintf("unknown level(%d)", l)
 	}
 }
 
-// valid 校验是否是合法的日志级别
+// valid 校验是否是合法的日志级别，包括内置级别和自定义注册的级别
 func (l LoggerLevel) valid() bool {
-	return l <= _maxLevel && l >= _minLevel
+	if l.builtin() {
+		return true
+	}
+
+	_, ok := l.registered()
+	return ok
+}
+
+// Valid 校验是否是合法的日志级别，包括内置级别和自定义注册的级别
+func (l LoggerLevel) Valid() bool {
+	return l.valid()
+}
+
+// Enabled 当前的日志级别为l时，是否允许打印level级别的日志，level不低于l时返回true
+func (l LoggerLevel) Enabled(level LoggerLevel) bool {
+	return l <= level
 }
 
 // Prohibit 校验日志级别，如果当前的日志级别比允许的级别高就返回为false，
 // 允许打印日志，返回返回为true，禁止打印日志
+//
+// Deprecated: 返回值的含义与名称相反，使用Enabled
 func (l LoggerLevel) Prohibit(level LoggerLevel) bool {
-	return l <= level
+	return l.Enabled(level)
+}
+
+// MarshalText 实现encoding.TextMarshaler接口，输出小写格式的日志级别
+func (l LoggerLevel) MarshalText() ([]byte, error) {
+	if !l.valid() {
+		return nil, fmt.Errorf("%w: %d", errorx.ErrLevelInvalid, l)
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText 实现encoding.TextUnmarshaler接口，支持从YAML/TOML/JSON等配置文件中
+// 解析日志级别，大小写均可
+func (l *LoggerLevel) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+	if level, ok := builtinLevel(name); ok {
+		*l = level
+		return nil
+	}
+
+	levelRegistryMu.RLock()
+	level, ok := lookupRegistered(name)
+	levelRegistryMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", errorx.ErrLevelInvalid, text)
+	}
+
+	*l = level
+	return nil
+}
+
+// builtinLevel 查找小写名称对应的内置日志级别
+func builtinLevel(name string) (LoggerLevel, bool) {
+	switch name {
+	case "trace":
+		return TraceLevel, true
+	case "debug":
+		return DebugLevel, true
+	case "info":
+		return InfoLevel, true
+	case "notice":
+		return NoticeLevel, true
+	case "warn":
+		return WarnLevel, true
+	case "error":
+		return ErrorLevel, true
+	case "panic":
+		return PanicLevel, true
+	case "fatal":
+		return FatalLevel, true
+	default:
+		return 0, false
+	}
+}
+
+// lookupRegistered 从自定义注册的级别中查找小写名称对应的日志级别，调用方需要持有levelRegistryMu
+func lookupRegistered(name string) (LoggerLevel, bool) {
+	for level, n := range levelRegistry {
+		if n == name {
+			return level, true
+		}
+	}
+
+	return 0, false
 }
 
 type LevelChecker interface {
@@ -15,8 +15,12 @@
 package core
 
 import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/TimeWtr/logx/errorx"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,9 +41,34 @@ func TestLevel(t *testing.T) {
 			level:   100,
 			wantRes: false,
 		},
+		{
+			name:    "合法level_TraceLevel",
+			level:   TraceLevel,
+			wantRes: true,
+		},
 		{
 			name:    "不合法level_2",
-			level:   DebugLevel - 1,
+			level:   TraceLevel - 1,
+			wantRes: false,
+		},
+		{
+			name:    "合法level_最小边界",
+			level:   _minLevel,
+			wantRes: true,
+		},
+		{
+			name:    "合法level_最大边界",
+			level:   _maxLevel,
+			wantRes: true,
+		},
+		{
+			name:    "不合法level_低于最小边界",
+			level:   _minLevel - 1,
+			wantRes: false,
+		},
+		{
+			name:    "不合法level_高于最大边界",
+			level:   _maxLevel + 1,
 			wantRes: false,
 		},
 	}
@@ -105,8 +134,137 @@ func TestProhibit(t *testing.T) {
 			assert.Equal(t, tc.valid, res)
 			allow := tc.level.Prohibit(tc.input)
 			assert.Equal(t, tc.wantRes, allow)
+			assert.Equal(t, tc.wantRes, tc.level.Enabled(tc.input))
 			t.Log(tc.level.String())
 			t.Log(tc.level.UpperString())
 		})
 	}
 }
+
+func TestLevel_Text(t *testing.T) {
+	t.Parallel()
+	for level := _minLevel; level <= _maxLevel; level++ {
+		text, err := level.MarshalText()
+		assert.NoError(t, err)
+		assert.Equal(t, level.String(), string(text))
+
+		var res LoggerLevel
+		assert.NoError(t, res.UnmarshalText(text))
+		assert.Equal(t, level, res)
+
+		// 大写格式同样可以解析
+		res = 0
+		assert.NoError(t, res.UnmarshalText([]byte(level.UpperString())))
+		assert.Equal(t, level, res)
+	}
+
+	var res LoggerLevel
+	assert.ErrorIs(t, res.UnmarshalText([]byte("verbose")), errorx.ErrLevelInvalid)
+	_, err := LoggerLevel(100).MarshalText()
+	assert.ErrorIs(t, err, errorx.ErrLevelInvalid)
+
+	var cfg struct {
+		Level LoggerLevel `json:"level"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"level":"WARN"}`), &cfg))
+	assert.Equal(t, WarnLevel, cfg.Level)
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"level":"warn"}`, string(data))
+}
+
+func TestRegisterLevel(t *testing.T) {
+	const verboseLevel LoggerLevel = 0
+	const auditLevel LoggerLevel = 10
+	t.Cleanup(func() {
+		levelRegistryMu.Lock()
+		defer levelRegistryMu.Unlock()
+		delete(levelRegistry, verboseLevel)
+		delete(levelRegistry, auditLevel)
+	})
+
+	assert.NoError(t, RegisterLevel("Verbose", verboseLevel))
+	assert.NoError(t, RegisterLevel("audit", auditLevel))
+	assert.Equal(t, "verbose", verboseLevel.String())
+	assert.Equal(t, "VERBOSE", verboseLevel.UpperString())
+	assert.True(t, verboseLevel.valid())
+	assert.True(t, verboseLevel.Enabled(TraceLevel))
+
+	var res LoggerLevel
+	assert.NoError(t, res.UnmarshalText([]byte("AUDIT")))
+	assert.Equal(t, auditLevel, res)
+	text, err := auditLevel.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "audit", string(text))
+
+	testCases := []struct {
+		name    string
+		level   string
+		value   LoggerLevel
+		wantErr error
+	}{
+		{
+			name:    "内置级别数值冲突",
+			level:   "audit",
+			value:   InfoLevel,
+			wantErr: errorx.ErrLevelConflict,
+		},
+		{
+			name:    "内置级别名称冲突",
+			level:   "WARN",
+			value:   20,
+			wantErr: errorx.ErrLevelConflict,
+		},
+		{
+			name:    "已注册级别数值冲突",
+			level:   "audit",
+			value:   verboseLevel,
+			wantErr: errorx.ErrLevelConflict,
+		},
+		{
+			name:    "已注册级别名称冲突",
+			level:   "verbose",
+			value:   20,
+			wantErr: errorx.ErrLevelConflict,
+		},
+		{
+			name:    "名称为空",
+			level:   " ",
+			value:   20,
+			wantErr: errorx.ErrLevelInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, RegisterLevel(tc.level, tc.value), tc.wantErr)
+		})
+	}
+}
+
+func TestRegisterLevel_Concurrent(t *testing.T) {
+	const base LoggerLevel = 100
+	const n = 20
+	t.Cleanup(func() {
+		levelRegistryMu.Lock()
+		defer levelRegistryMu.Unlock()
+		for i := LoggerLevel(0); i < n; i++ {
+			delete(levelRegistry, base+i)
+		}
+	})
+
+	// 并发注册同名的级别，只有一个注册成功
+	var succeeded atomic.Int32
+	var wg sync.WaitGroup
+	for i := LoggerLevel(0); i < n; i++ {
+		wg.Add(1)
+		go func(value LoggerLevel) {
+			defer wg.Done()
+			if RegisterLevel("security", value) == nil {
+				succeeded.Add(1)
+			}
+		}(base + i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), succeeded.Load())
+}
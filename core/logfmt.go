@@ -0,0 +1,101 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogfmtFormatter logfmt格式的格式化器，输出格式如下：
+// ts=2006-01-02T15:04:05Z level=info msg="hello world" caller=foo/bar.go:42 key=value
+type LogfmtFormatter struct{}
+
+func NewLogfmtFormatter() BinaryFormatter {
+	return &LogfmtFormatter{}
+}
+
+func (f *LogfmtFormatter) Format(e Entity) []byte {
+	var builder strings.Builder
+	writeLogfmtPair(&builder, "ts", time.Unix(0, e.Timestamp).UTC().Format(time.RFC3339))
+	writeLogfmtPair(&builder, "level", e.Level.String())
+	writeLogfmtPair(&builder, "msg", e.Message)
+	if len(e.CE) > 0 && e.CE[0].ok {
+		const callerParts = 2
+		writeLogfmtPair(&builder, "caller", e.CE[0].getFile(callerParts)+":"+strconv.Itoa(e.CE[0].line))
+	}
+	if e.TraceID != "" {
+		writeLogfmtPair(&builder, "trace_id", e.TraceID)
+	}
+	if e.Service != "" {
+		writeLogfmtPair(&builder, "service", e.Service)
+	}
+
+	// map的遍历顺序是随机的，按照key排序保证输出稳定
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		val, ok := e.Fields[key].(string)
+		if !ok {
+			val = fmt.Sprintf("%v", e.Fields[key])
+		}
+		writeLogfmtPair(&builder, key, val)
+	}
+
+	return []byte(builder.String())
+}
+
+// writeLogfmtPair 写入key=value，value按照WriteLogfmtValue的规则编码
+func writeLogfmtPair(builder *strings.Builder, key, val string) {
+	if builder.Len() > 0 {
+		builder.WriteByte(' ')
+	}
+	builder.WriteString(key)
+	builder.WriteByte('=')
+	WriteLogfmtValue(builder, val)
+}
+
+// WriteLogfmtValue 写入logfmt格式的值，value中包含空格、等号、双引号或者为空时使用双引号包裹，
+// 内部的双引号和反斜杠使用反斜杠转义，文本格式的结构化字段和logfmt格式化器共用这一规则
+func WriteLogfmtValue(builder *strings.Builder, val string) {
+	if val != "" && !strings.ContainsAny(val, " =\"\\\t\r\n") {
+		builder.WriteString(val)
+		return
+	}
+
+	builder.WriteByte('"')
+	for _, r := range val {
+		switch r {
+		case '"', '\\':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case '\n':
+			builder.WriteString("\\n")
+		case '\r':
+			builder.WriteString("\\r")
+		case '\t':
+			builder.WriteString("\\t")
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	builder.WriteByte('"')
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogfmtFormatter_Format(t *testing.T) {
+	ts := time.Date(2025, 5, 12, 12, 12, 0, 0, time.UTC).UnixNano()
+	testCases := []struct {
+		name    string
+		entity  Entity
+		wantRes string
+	}{
+		{
+			name: "普通消息",
+			entity: Entity{
+				Timestamp: ts,
+				Level:     InfoLevel,
+				Message:   "hello",
+			},
+			wantRes: "ts=2025-05-12T12:12:00Z level=info msg=hello",
+		},
+		{
+			name: "消息包含空格和引号",
+			entity: Entity{
+				Timestamp: ts,
+				Level:     ErrorLevel,
+				Message:   `say "hello world"`,
+			},
+			wantRes: `ts=2025-05-12T12:12:00Z level=error msg="say \"hello world\""`,
+		},
+		{
+			name: "结构化字段",
+			entity: Entity{
+				Timestamp: ts,
+				Level:     WarnLevel,
+				Message:   "hello world",
+				Service:   "api",
+				Fields: map[string]any{
+					"uid":   1001,
+					"path":  "/a=b",
+					"cost":  1.5,
+					"empty": "",
+				},
+			},
+			wantRes: `ts=2025-05-12T12:12:00Z level=warn msg="hello world" service=api ` +
+				`cost=1.5 empty="" path="/a=b" uid=1001`,
+		},
+		{
+			name: "堆栈信息",
+			entity: Entity{
+				Timestamp: ts,
+				Level:     DebugLevel,
+				Message:   "hello",
+				CE:        []CallerEntity{{file: "/src/foo/bar.go", line: 42, ok: true}},
+			},
+			wantRes: "ts=2025-05-12T12:12:00Z level=debug msg=hello caller=foo/bar.go:42",
+		},
+	}
+
+	f := NewLogfmtFormatter()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.wantRes, string(f.Format(tc.entity)))
+		})
+	}
+}
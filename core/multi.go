@@ -0,0 +1,130 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// PriorityWriter 带有优先级的写入器，优先级越小越先写入
+type PriorityWriter struct {
+	// 写入器的唯一标识，用于RemoveWriter
+	Key string
+	// 优先级
+	Priority int
+	Writer
+}
+
+// MultiWriter 按照优先级顺序分发日志的写入器，比如指标写入器需要先于文件写入器调用，
+// 优先级相同时按照添加的顺序写入
+type MultiWriter struct {
+	// 并发保护
+	lock sync.RWMutex
+	// 按照优先级升序排列的写入器
+	writers []PriorityWriter
+}
+
+func NewMultiWriter() *MultiWriter {
+	return &MultiWriter{}
+}
+
+// AddWriter 按照优先级添加写入器，key已经存在时替换原来的写入器并重新排序
+func (m *MultiWriter) AddWriter(key string, w Writer, priority int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.writers = slices.DeleteFunc(m.writers, func(pw PriorityWriter) bool {
+		return pw.Key == key
+	})
+	// 插入到第一个优先级更大的写入器之前，保证相同优先级按照添加的顺序排列
+	idx := slices.IndexFunc(m.writers, func(pw PriorityWriter) bool {
+		return pw.Priority > priority
+	})
+	if idx < 0 {
+		idx = len(m.writers)
+	}
+	m.writers = slices.Insert(m.writers, idx, PriorityWriter{Key: key, Priority: priority, Writer: w})
+}
+
+// RemoveWriter 移除写入器，不会关闭被移除的写入器
+func (m *MultiWriter) RemoveWriter(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.writers = slices.DeleteFunc(m.writers, func(pw PriorityWriter) bool {
+		return pw.Key == key
+	})
+}
+
+// Writers 返回按照优先级排列的写入器
+func (m *MultiWriter) Writers() []PriorityWriter {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return slices.Clone(m.writers)
+}
+
+// Write 按照优先级依次写入，某个写入器失败时继续写入其他的写入器，通过errors.Join聚合所有的错误
+func (m *MultiWriter) Write(p []byte) (n int, err error) {
+	err = m.each(func(w Writer) error {
+		_, err := w.Write(p)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (m *MultiWriter) WriteEntity(e Entity) error {
+	return m.each(func(w Writer) error {
+		return w.WriteEntity(e)
+	})
+}
+
+func (m *MultiWriter) Flush() error {
+	return m.each(Writer.Flush)
+}
+
+func (m *MultiWriter) Close() error {
+	return m.each(Writer.Close)
+}
+
+// Rotate 轮转所有实现了Rotator接口的写入器，其他的写入器被忽略
+func (m *MultiWriter) Rotate() error {
+	return m.each(func(w Writer) error {
+		if r, ok := w.(Rotator); ok {
+			return r.Rotate()
+		}
+		return nil
+	})
+}
+
+func (m *MultiWriter) each(fn func(w Writer) error) error {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	var errs []error
+	for _, pw := range m.writers {
+		if err := fn(pw.Writer); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orderWriter 记录写入的顺序
+type orderWriter struct {
+	mockWriter
+	name  string
+	order *[]string
+}
+
+func (w *orderWriter) Write(p []byte) (int, error) {
+	*w.order = append(*w.order, w.name)
+	return w.mockWriter.Write(p)
+}
+
+func TestMultiWriter(t *testing.T) {
+	var order []string
+	newWriter := func(name string) *orderWriter {
+		return &orderWriter{name: name, order: &order}
+	}
+
+	m := NewMultiWriter()
+	m.AddWriter("file", newWriter("file"), 10)
+	m.AddWriter("metrics", newWriter("metrics"), 0)
+	m.AddWriter("stdout", newWriter("stdout"), 10)
+	m.AddWriter("kafka", newWriter("kafka"), 5)
+	m.AddWriter("remote", newWriter("remote"), 10)
+
+	_, err := m.Write([]byte("a"))
+	assert.NoError(t, err)
+	// 优先级升序，相同优先级按照添加的顺序
+	assert.Equal(t, []string{"metrics", "kafka", "file", "stdout", "remote"}, order)
+
+	// 替换已经存在的key后重新排序
+	order = nil
+	m.AddWriter("file", newWriter("file"), -1)
+	m.RemoveWriter("kafka")
+	m.RemoveWriter("missing")
+	_, err = m.Write([]byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"file", "metrics", "stdout", "remote"}, order)
+	assert.Len(t, m.Writers(), 4)
+
+	// 某个写入器失败时其他的写入器仍然会写入
+	mockErr := errors.New("mock error")
+	failed := &mockWriter{fails: 1, err: mockErr}
+	m.AddWriter("failed", failed, -10)
+	order = nil
+	n, err := m.Write([]byte("a"))
+	assert.ErrorIs(t, err, mockErr)
+	assert.Equal(t, 0, n)
+	assert.Len(t, order, 4)
+
+	assert.NoError(t, m.WriteEntity(Entity{Message: "b"}))
+	assert.NoError(t, m.Flush())
+	assert.NoError(t, m.Close())
+	assert.True(t, failed.closed)
+}
+
+// rotateWriter 记录轮转次数的写入器
+type rotateWriter struct {
+	mockWriter
+	rotates int
+	err     error
+}
+
+func (w *rotateWriter) Rotate() error {
+	w.rotates++
+	return w.err
+}
+
+func TestMultiWriter_Rotate(t *testing.T) {
+	mockErr := errors.New("mock error")
+	r1, r2 := &rotateWriter{}, &rotateWriter{err: mockErr}
+	m := NewMultiWriter()
+	m.AddWriter("r1", r1, 0)
+	m.AddWriter("plain", &mockWriter{}, 0)
+	m.AddWriter("r2", r2, 0)
+
+	assert.ErrorIs(t, m.Rotate(), mockErr)
+	assert.Equal(t, 1, r1.rotates)
+	assert.Equal(t, 1, r2.rotates)
+
+	m.RemoveWriter("r2")
+	assert.NoError(t, m.Rotate())
+	assert.Equal(t, 2, r1.rotates)
+}
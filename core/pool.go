@@ -16,16 +16,71 @@ package core
 
 import (
 	"errors"
+	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/TimeWtr/logx/errorx"
 )
 
 type Stats struct {
-	allocations atomic.Int64 // 总共分配的对象数量
+	allocations atomic.Int64 // 存活的对象数量(包括预先生成的对象)，用于限制对象的数量
 	totalGets   atomic.Int64 // 总共获取的对象数量
+	reuses      atomic.Int64 // 从池中获取到可用对象的次数
 	discards    atomic.Int64 // 因为池满丢弃的对象数量
+	casRetries  atomic.Int64 // CAS失败重试的次数，用于衡量竞争程度
+}
+
+// ExtendedStats 对象池的完整统计信息
+type ExtendedStats struct {
+	// 分配的对象数量
+	Allocations int64
+	// 复用的对象数量
+	Reuses int64
+	// 因为池满丢弃的对象数量
+	Discards int64
+	// CAS失败重试的次数
+	CASRetries int64
+}
+
+// PoolStats 对象池的健康状态，用于健康检查和监控接口
+type PoolStats struct {
+	// 分配的对象数量
+	Allocations int64
+	// 复用的对象数量
+	Reuses int64
+	// 因为池满丢弃的对象数量
+	Discards int64
+	// 当前池中的可用对象数量
+	CurrentCount int64
+	// 池中允许的最大对象数量
+	MaxSize int64
+}
+
+const (
+	// DefaultSpinLimit Get获取失败后让出CPU的默认次数，超过后开始休眠
+	DefaultSpinLimit = 8
+	// maxBackoff Get获取失败后休眠的最大间隔
+	maxBackoff = time.Millisecond
+	// maxBackoffShift 休眠间隔的最大指数，1微秒左移10位已经超过maxBackoff
+	maxBackoffShift = 10
+)
+
+// poolOptions 对象池的可选配置，和对象的类型无关，所以不使用泛型
+type poolOptions struct {
+	// Get获取失败后让出CPU的次数
+	spinLimit int
+}
+
+type PoolOptions func(*poolOptions)
+
+// WithSpinLimit 设置Get获取失败后让出CPU的次数，超过后按照1微秒、2微秒指数增长休眠，
+// 最多休眠1毫秒，避免对象数量达到上限时自旋占满CPU，小于0时按照0处理
+func WithSpinLimit(n int) PoolOptions {
+	return func(o *poolOptions) {
+		o.spinLimit = max(n, 0)
+	}
 }
 
 type WrapPool[T any] struct {
@@ -37,19 +92,27 @@ type WrapPool[T any] struct {
 	newFunc      func() T      // 创建对象函数
 	closeFunc    func(T)       // 在关闭Pool时关闭资源的方法
 	sig          chan struct{} // 关闭的信号通知
+	spinLimit    int           // Get获取失败后让出CPU的次数
 }
 
-func NewWrapPool[T any](fn func() T, resetFn func(T) T, closeFunc func(T), maxSize int32) (*WrapPool[T], error) {
+func NewWrapPool[T any](fn func() T, resetFn func(T) T, closeFunc func(T), maxSize int32,
+	opts ...PoolOptions) (*WrapPool[T], error) {
 	if fn == nil {
 		return nil, errors.New("newFunc cannot be nil")
 	}
 
+	o := poolOptions{spinLimit: DefaultSpinLimit}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	p := &WrapPool[T]{
 		newFunc:   fn,
 		resetFunc: resetFn,
 		closeFunc: closeFunc,
 		stats:     Stats{},
 		sig:       make(chan struct{}),
+		spinLimit: o.spinLimit,
 	}
 
 	p.maxSize.Store(maxSize)
@@ -59,54 +122,63 @@ func NewWrapPool[T any](fn func() T, resetFn func(T) T, closeFunc func(T), maxSi
 		},
 	}
 
-	// 预先生成最大数量30%的对象，提高性能，同时不过多消耗启动时间
+	// 预先生成最大数量30%的对象，提高性能，同时不过多消耗启动时间，预先生成的对象同样计入
+	// 存活的对象数量，否则缩小maxSize后归还这些对象时allocations会被减为负数
 	const scale = 0.3
 	preloadSize := int(float64(maxSize) * scale)
 	for i := 0; i < preloadSize; i++ {
 		obj := p.p.Get()
 		p.p.Put(obj)
 		p.currentCount.Add(1)
+		p.stats.allocations.Add(1)
 	}
 
 	return p, nil
 }
 
 func (p *WrapPool[T]) Get() (T, error) {
+	return p.get(true)
+}
+
+// TryGet 非阻塞的获取对象，池中没有可用对象并且分配的数量已经达到上限时立即返回
+// errorx.ErrPoolMaxSize，不等待其他协程归还对象
+func (p *WrapPool[T]) TryGet() (T, error) {
+	return p.get(false)
+}
+
+// get 获取对象，wait为true时分配的数量达到上限后退避等待其他协程归还对象
+func (p *WrapPool[T]) get(wait bool) (T, error) {
 	var t T
 	if p == nil {
 		return t, errorx.ErrBufferClose
 	}
 
-	for {
+	for retries := 0; ; retries++ {
 		select {
 		case <-p.sig:
 			return t, errorx.ErrBufferClose
 		default:
 		}
+		if retries > 0 && wait {
+			p.backoff(retries)
+		}
 
+		// 优先从池中获取可用对象
 		current := p.currentCount.Load()
-		if current <= 0 {
-			// 池中无可用对象
-			break
-		}
+		if current > 0 {
+			if p.currentCount.CompareAndSwap(current, current-1) {
+				t, ok := p.p.Get().(T)
+				if !ok {
+					p.currentCount.Add(1)
+					return t, errorx.ErrPoolType
+				}
 
-		if p.currentCount.CompareAndSwap(current, current-1) {
-			t, ok := p.p.Get().(T)
-			if !ok {
-				p.currentCount.Add(1)
-				return t, errorx.ErrPoolType
+				p.stats.totalGets.Add(1)
+				p.stats.reuses.Add(1)
+				return t, nil
 			}
-
-			p.stats.totalGets.Add(1)
-			return t, nil
-		}
-	}
-
-	for {
-		select {
-		case <-p.sig:
-			return t, errorx.ErrBufferClose
-		default:
+			p.stats.casRetries.Add(1)
+			continue
 		}
 
 		allocated := p.stats.allocations.Load()
@@ -114,22 +186,35 @@ func (p *WrapPool[T]) Get() (T, error) {
 			return t, errorx.ErrPoolMaxSize
 		}
 
-		// 二次验证
-		if p.stats.allocations.Load() < int64(p.maxSize.Load()) {
+		if allocated < int64(p.maxSize.Load()) {
 			if p.stats.allocations.CompareAndSwap(allocated, allocated+1) {
 				p.stats.totalGets.Add(1)
 				return p.newFunc(), nil
 			}
+			p.stats.casRetries.Add(1)
+			continue
+		}
+		if !wait {
+			return t, errorx.ErrPoolMaxSize
 		}
+		// 分配的数量已经达到上限，退避后重新检查池中是否有其他协程归还的对象
 	}
 }
 
+// backoff 第retries次获取失败后退避，前spinLimit次让出CPU，之后指数增长休眠，最多休眠maxBackoff
+func (p *WrapPool[T]) backoff(retries int) {
+	if retries <= p.spinLimit {
+		runtime.Gosched()
+		return
+	}
+
+	shift := min(retries-p.spinLimit-1, maxBackoffShift)
+	time.Sleep(min(time.Microsecond<<shift, maxBackoff))
+}
+
 func (p *WrapPool[T]) Put(t T) {
+	// 空的对象池没有closeFunc，无法释放资源，直接丢弃
 	if p == nil {
-		if p.closeFunc != nil {
-			p.closeFunc(t)
-		}
-
 		return
 	}
 
@@ -158,14 +243,101 @@ func (p *WrapPool[T]) Put(t T) {
 			p.p.Put(t)
 			return
 		}
+		p.stats.casRetries.Add(1)
 	}
 }
 
+// Stats 返回分配、复用和丢弃的对象数量，allocations为Get新创建对象的次数，reuses为Get从池中
+// 获取到可用对象(包括预先生成的对象)的次数，两者之和等于Get成功的次数
 func (p *WrapPool[T]) Stats() (allocations, reuses, discards int64) {
+	// 先读取reuses，并发的Get不会使allocations小于0
+	r := p.stats.reuses.Load()
 	t := p.stats.totalGets.Load()
-	a := p.stats.allocations.Load()
 	d := p.stats.discards.Load()
-	return a, t - a, d
+	return t - r, r, d
+}
+
+// Contention 返回Get和Put中CAS失败重试的次数，次数过多说明竞争激烈，需要调整maxSize
+func (p *WrapPool[T]) Contention() int64 {
+	return p.stats.casRetries.Load()
+}
+
+// ExtendedStats 返回包括CAS重试次数在内的完整统计信息
+func (p *WrapPool[T]) ExtendedStats() ExtendedStats {
+	a, r, d := p.Stats()
+	return ExtendedStats{
+		Allocations: a,
+		Reuses:      r,
+		Discards:    d,
+		CASRetries:  p.Contention(),
+	}
+}
+
+// CurrentCount 返回当前池中的可用对象数量
+func (p *WrapPool[T]) CurrentCount() int32 {
+	return p.currentCount.Load()
+}
+
+// PoolStats 返回统计计数和当前的容量信息
+func (p *WrapPool[T]) PoolStats() PoolStats {
+	a, r, d := p.Stats()
+	return PoolStats{
+		Allocations:  a,
+		Reuses:       r,
+		Discards:     d,
+		CurrentCount: int64(p.currentCount.Load()),
+		MaxSize:      int64(p.maxSize.Load()),
+	}
+}
+
+// ResetStats 清零统计计数，用于按照时间窗口统计，用于限制对象数量的存活对象数量不会清零
+func (p *WrapPool[T]) ResetStats() {
+	p.stats.totalGets.Store(0)
+	p.stats.reuses.Store(0)
+	p.stats.discards.Store(0)
+	p.stats.casRetries.Store(0)
+}
+
+// ForEach 取出池中所有可用的对象依次调用fn，之后原样放回对象池(不会调用resetFunc)，
+// 返回访问的对象数量，用于优雅关闭前检查对象的状态或者预热对象。ForEach不会加锁，只依赖
+// currentCount的CAS保证取出和放回的数量一致，不能和Get、Put并发调用。sync.Pool在GC时
+// 可能丢弃对象，被丢弃的位置由New重新创建，所以访问的是尽力而为的快照，不保证是之前放回的对象
+func (p *WrapPool[T]) ForEach(fn func(T)) int {
+	if p == nil || fn == nil {
+		return 0
+	}
+
+	select {
+	case <-p.sig:
+		return 0
+	default:
+	}
+
+	objs := make([]T, 0, p.currentCount.Load())
+	for {
+		current := p.currentCount.Load()
+		if current <= 0 {
+			break
+		}
+
+		if p.currentCount.CompareAndSwap(current, current-1) {
+			obj, ok := p.p.Get().(T)
+			if !ok {
+				// 与Get一致，恢复计数后停止取出，避免计数和池中的对象不一致
+				p.currentCount.Add(1)
+				break
+			}
+			objs = append(objs, obj)
+		}
+	}
+
+	for _, obj := range objs {
+		fn(obj)
+		p.p.Put(obj)
+		p.currentCount.Add(1)
+	}
+
+	return len(objs)
 }
 
 func (p *WrapPool[T]) Close() {
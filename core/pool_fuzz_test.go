@@ -0,0 +1,109 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+)
+
+// 模糊测试的操作类型，每个字节的低3位为操作类型，高位为操作参数
+const (
+	fuzzOpGet = iota
+	fuzzOpPut
+	fuzzOpResize
+	fuzzOpForEach
+	fuzzOpClose
+	fuzzOpCount
+)
+
+// checkPoolInvariants 检查对象池的计数不变量
+func checkPoolInvariants(t *testing.T, p *WrapPool[*int], step int) {
+	current, maxSize := p.currentCount.Load(), p.maxSize.Load()
+	if current < 0 {
+		t.Fatalf("step %d: currentCount(%d) < 0", step, current)
+	}
+	if current > maxSize {
+		t.Fatalf("step %d: currentCount(%d) > maxSize(%d)", step, current, maxSize)
+	}
+	allocations, _, discards := p.Stats()
+	if allocations < 0 || discards < 0 {
+		t.Fatalf("step %d: allocations(%d) or discards(%d) < 0", step, allocations, discards)
+	}
+	if live := p.stats.allocations.Load(); live < 0 {
+		t.Fatalf("step %d: live objects(%d) < 0", step, live)
+	}
+}
+
+// FuzzWrapPool 随机执行Get、Put、Resize、ForEach和Close，每一步之后检查计数不变量，
+// Get在池为空并且分配数量已经达到上限时会一直自旋，所以跳过这种情况
+func FuzzWrapPool(f *testing.F) {
+	f.Add(uint8(10), []byte{fuzzOpGet, fuzzOpGet, fuzzOpPut, fuzzOpPut, fuzzOpForEach})
+	// 对象全部借出后缩小maxSize，再全部归还
+	f.Add(uint8(10), []byte{
+		fuzzOpGet, fuzzOpGet, fuzzOpGet, fuzzOpGet,
+		1<<3 | fuzzOpResize,
+		fuzzOpPut, fuzzOpPut, fuzzOpPut, fuzzOpPut,
+	})
+	// 池中有可用对象时缩小maxSize
+	f.Add(uint8(16), []byte{2<<3 | fuzzOpResize, fuzzOpGet, fuzzOpPut, 8<<3 | fuzzOpResize, fuzzOpPut})
+	f.Add(uint8(4), []byte{fuzzOpGet, fuzzOpClose, fuzzOpPut, fuzzOpGet, fuzzOpResize, fuzzOpForEach})
+
+	f.Fuzz(func(t *testing.T, size uint8, ops []byte) {
+		maxSize := int32(size%32) + 1
+		p, err := NewWrapPool[*int](func() *int {
+			return new(int)
+		}, func(v *int) *int {
+			*v = 0
+			return v
+		}, func(_ *int) {}, maxSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var borrowed []*int
+		closed := false
+		for step, op := range ops {
+			arg := int32(op >> 3)
+			switch op & 0x07 % fuzzOpCount {
+			case fuzzOpGet:
+				if p.currentCount.Load() <= 0 && p.stats.allocations.Load() >= int64(p.maxSize.Load()) {
+					continue
+				}
+				v, err := p.Get()
+				if err == nil {
+					borrowed = append(borrowed, v)
+				}
+			case fuzzOpPut:
+				if len(borrowed) == 0 {
+					continue
+				}
+				p.Put(borrowed[len(borrowed)-1])
+				borrowed = borrowed[:len(borrowed)-1]
+			case fuzzOpResize:
+				p.adjustMaxSize(arg%32 + 1)
+			case fuzzOpForEach:
+				p.ForEach(func(v *int) {
+					*v++
+				})
+			case fuzzOpClose:
+				if !closed {
+					p.Close()
+					closed = true
+				}
+			}
+			checkPoolInvariants(t, p, step)
+		}
+	})
+}
@@ -61,6 +61,81 @@ func TestCounting_int(t *testing.T) {
 	t.Logf("totalGets计数: %d, allocations计数：%d", p.stats.totalGets.Load(), p.stats.allocations.Load())
 }
 
+func TestWrapPool_ExtendedStats(t *testing.T) {
+	p, err := NewWrapPool[int](func() int { return -1 }, nil, nil, 10)
+	assert.NoError(t, err)
+
+	const total = 1000
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			obj, err := p.Get()
+			assert.NoError(t, err)
+			p.Put(obj)
+		}()
+	}
+	wg.Wait()
+
+	stats := p.ExtendedStats()
+	assert.Equal(t, int64(total), stats.Allocations+stats.Reuses)
+	assert.Equal(t, p.Contention(), stats.CASRetries)
+	t.Logf("CAS重试次数: %d", stats.CASRetries)
+
+	// 重置后统计计数清零，存活的对象数量保留，用于限制对象的数量
+	p.ResetStats()
+	assert.Equal(t, ExtendedStats{}, p.ExtendedStats())
+	obj, err := p.Get()
+	assert.NoError(t, err)
+	p.Put(obj)
+	after := p.ExtendedStats()
+	assert.Equal(t, int64(1), after.Allocations+after.Reuses)
+}
+
+func TestWrapPool_ForEach(t *testing.T) {
+	p, err := NewWrapPool[chan string](
+		func() chan string { return make(chan string, 1) },
+		nil,
+		func(ch chan string) { close(ch) },
+		10,
+	)
+	assert.NoError(t, err)
+
+	// 预先生成最大数量30%的对象
+	visited := p.ForEach(func(ch chan string) {
+		assert.Len(t, ch, 0)
+	})
+	assert.Equal(t, 3, visited)
+	assert.Equal(t, int32(3), p.currentCount.Load())
+
+	ch, err := p.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, p.ForEach(func(chan string) {}))
+	p.Put(ch)
+	assert.Equal(t, 3, p.ForEach(func(chan string) {}))
+	assert.Equal(t, 0, p.ForEach(nil))
+
+	p.Close()
+	assert.Equal(t, 0, p.ForEach(func(chan string) {}))
+}
+
+func TestWrapPool_ForEachTypeMismatch(t *testing.T) {
+	p, err := NewWrapPool[chan string](
+		func() chan string { return make(chan string, 1) },
+		nil,
+		nil,
+		10,
+	)
+	assert.NoError(t, err)
+
+	// 池中取出的对象类型不匹配时恢复计数并停止，不会死循环或者丢失计数
+	p.p = &sync.Pool{New: func() any { return "mismatch" }}
+	assert.Equal(t, 0, p.ForEach(func(chan string) {}))
+	assert.Equal(t, int32(3), p.currentCount.Load())
+}
+
 func TestCounting_string(t *testing.T) {
 	p, err := NewWrapPool[string](
 		func() string { return "" },
@@ -273,3 +348,98 @@ func TestCounting_chan_multi_adjust(t *testing.T) {
 	}
 	t.Logf("totalGets计数: %d, allocations计数：%d", p.stats.totalGets.Load(), p.stats.allocations.Load())
 }
+
+func TestWrapPool_NilPut(t *testing.T) {
+	var p *WrapPool[int]
+	assert.NotPanics(t, func() {
+		p.Put(1)
+	})
+	_, err := p.Get()
+	assert.ErrorIs(t, err, errorx.ErrBufferClose)
+}
+
+func TestWrapPool_PoolStats(t *testing.T) {
+	p, err := NewWrapPool[int](func() int { return -1 }, nil, nil, 10)
+	assert.NoError(t, err)
+	// 预先生成30%的对象，不计入Get的统计
+	assert.Equal(t, int32(3), p.CurrentCount())
+	assert.Equal(t, PoolStats{CurrentCount: 3, MaxSize: 10}, p.PoolStats())
+
+	objs := make([]int, 0, 4)
+	for i := 0; i < 4; i++ {
+		obj, err := p.Get()
+		assert.NoError(t, err)
+		objs = append(objs, obj)
+	}
+	// 获取预先生成的对象计为复用，池为空之后新创建对象
+	assert.Equal(t, PoolStats{Allocations: 1, Reuses: 3, CurrentCount: 0, MaxSize: 10}, p.PoolStats())
+	for _, obj := range objs {
+		p.Put(obj)
+	}
+	assert.Equal(t, int32(4), p.CurrentCount())
+}
+
+func TestWrapPool_TryGet(t *testing.T) {
+	p, err := NewWrapPool[int](func() int { return 1 }, nil, nil, 1)
+	assert.NoError(t, err)
+
+	obj, err := p.TryGet()
+	assert.NoError(t, err)
+	// 分配的数量达到上限时不等待，立即返回
+	_, err = p.TryGet()
+	assert.ErrorIs(t, err, errorx.ErrPoolMaxSize)
+	p.Put(obj)
+	_, err = p.TryGet()
+	assert.NoError(t, err)
+
+	p.Close()
+	_, err = p.TryGet()
+	assert.ErrorIs(t, err, errorx.ErrBufferClose)
+}
+
+func TestWrapPool_Backoff(t *testing.T) {
+	p, err := NewWrapPool[int](func() int { return 1 }, nil, nil, 1, WithSpinLimit(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, p.spinLimit)
+
+	obj, err := p.Get()
+	assert.NoError(t, err)
+
+	// 分配的数量达到上限时退避等待，直到其他协程归还对象
+	got := make(chan error, 1)
+	go func() {
+		_, err := p.Get()
+		got <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-got:
+		t.Fatal("Get should wait for Put when the pool is exhausted")
+	default:
+	}
+	p.Put(obj)
+	select {
+	case err = <-got:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Get did not return after Put")
+	}
+
+	// 关闭之后等待中的Get立即返回
+	go func() {
+		_, err := p.Get()
+		got <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	p.Close()
+	select {
+	case err = <-got:
+		assert.ErrorIs(t, err, errorx.ErrBufferClose)
+	case <-time.After(time.Second):
+		t.Fatal("Get did not return after Close")
+	}
+
+	p, err = NewWrapPool[int](func() int { return 1 }, nil, nil, 1, WithSpinLimit(-1))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, p.spinLimit)
+}
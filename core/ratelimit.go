@@ -0,0 +1,82 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"sync/atomic"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitWriter 使用令牌桶限制写入速率的装饰器，防止错误风暴时压垮ES、Kafka等网络写入器
+type RateLimitWriter struct {
+	// 被装饰的写入器
+	base Writer
+	// 令牌桶
+	limiter *rate.Limiter
+	// 非阻塞写入时丢弃的日志条数
+	dropped atomic.Int64
+}
+
+// NewRateLimitWriter 创建限流写入器，rps为每秒允许写入的条数，burst为令牌桶的容量
+func NewRateLimitWriter(base Writer, rps float64, burst int) *RateLimitWriter {
+	return &RateLimitWriter{
+		base:    base,
+		limiter: rate.NewLimiter(rate.Limit(rps), burst),
+	}
+}
+
+// Write 阻塞等待令牌后写入
+func (r *RateLimitWriter) Write(p []byte) (n int, err error) {
+	if err = r.limiter.Wait(context.Background()); err != nil {
+		return 0, err
+	}
+
+	return r.base.Write(p)
+}
+
+// WriteEntity 阻塞等待令牌后写入
+func (r *RateLimitWriter) WriteEntity(e Entity) error {
+	if err := r.limiter.Wait(context.Background()); err != nil {
+		return err
+	}
+
+	return r.base.WriteEntity(e)
+}
+
+// WriteOrDrop 非阻塞写入，没有可用的令牌时直接丢弃并返回dropped为true
+func (r *RateLimitWriter) WriteOrDrop(p []byte) (dropped bool, err error) {
+	if !r.limiter.Allow() {
+		r.dropped.Add(1)
+		return true, nil
+	}
+
+	_, err = r.base.Write(p)
+	return false, err
+}
+
+// Dropped 返回非阻塞写入时丢弃的日志条数
+func (r *RateLimitWriter) Dropped() int64 {
+	return r.dropped.Load()
+}
+
+func (r *RateLimitWriter) Flush() error {
+	return r.base.Flush()
+}
+
+func (r *RateLimitWriter) Close() error {
+	return r.base.Close()
+}
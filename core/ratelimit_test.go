@@ -0,0 +1,50 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitWriter(t *testing.T) {
+	base := &mockWriter{}
+	w := NewRateLimitWriter(base, 20, 2)
+
+	// 令牌桶容量内的写入不会被丢弃
+	for i := 0; i < 2; i++ {
+		dropped, err := w.WriteOrDrop([]byte("a"))
+		assert.NoError(t, err)
+		assert.False(t, dropped)
+	}
+	dropped, err := w.WriteOrDrop([]byte("b"))
+	assert.NoError(t, err)
+	assert.True(t, dropped)
+	assert.Equal(t, int64(1), w.Dropped())
+
+	// 阻塞等待令牌，每秒20个令牌，等待的时间大约为50ms
+	start := time.Now()
+	n, err := w.Write([]byte("c"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, "aac", base.buf.String())
+
+	assert.NoError(t, w.Flush())
+	assert.NoError(t, w.Close())
+	assert.True(t, base.closed)
+}
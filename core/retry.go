@@ -0,0 +1,137 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+)
+
+// RetryPolicy 写入失败时的重试策略，重试的间隔按照Multiplier指数增长，不超过MaxDelay
+type RetryPolicy struct {
+	// 最大的重试次数，不包含第一次写入
+	MaxRetries int
+	// 第一次重试的间隔
+	InitialDelay time.Duration
+	// 最大的重试间隔
+	MaxDelay time.Duration
+	// 重试间隔的增长倍数，小于1时按照1处理
+	Multiplier float64
+}
+
+// delay 第attempt次重试(从0开始)的间隔，在[d/2, d]之间随机抖动，避免多个写入器同时重试
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := float64(p.InitialDelay)
+	for i := 0; i < attempt; i++ {
+		d *= p.Multiplier
+		if p.MaxDelay > 0 && d >= float64(p.MaxDelay) {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > float64(p.MaxDelay) {
+		d = float64(p.MaxDelay)
+	}
+
+	half := int64(d) / 2
+	if half <= 0 {
+		return time.Duration(d)
+	}
+
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+// RetryWriter 写入失败时按照RetryPolicy重试的装饰器，errorx.ErrBufferClose表示已经关闭，
+// 不会重试
+type RetryWriter struct {
+	// 被装饰的写入器
+	base Writer
+	// 重试策略
+	policy RetryPolicy
+	// 重试的次数
+	attempts atomic.Int64
+	// 成功的次数，包括重试后成功
+	successes atomic.Int64
+	// 重试后仍然失败的次数
+	failures atomic.Int64
+}
+
+func NewRetryWriter(base Writer, policy RetryPolicy) *RetryWriter {
+	if policy.MaxRetries < 0 {
+		policy.MaxRetries = 0
+	}
+	if policy.Multiplier < 1 {
+		policy.Multiplier = 1
+	}
+
+	return &RetryWriter{
+		base:   base,
+		policy: policy,
+	}
+}
+
+// Write 部分写入失败时只重试剩余未写入的数据
+func (r *RetryWriter) Write(p []byte) (n int, err error) {
+	err = r.retry(func() error {
+		written, err := r.base.Write(p[n:])
+		n += written
+		return err
+	})
+
+	return n, err
+}
+
+func (r *RetryWriter) WriteEntity(e Entity) error {
+	return r.retry(func() error {
+		return r.base.WriteEntity(e)
+	})
+}
+
+func (r *RetryWriter) Flush() error {
+	return r.retry(r.base.Flush)
+}
+
+func (r *RetryWriter) Close() error {
+	return r.base.Close()
+}
+
+// RetryStats 返回重试的次数、成功的次数和失败的次数
+func (r *RetryWriter) RetryStats() (attempts, successes, failures int64) {
+	return r.attempts.Load(), r.successes.Load(), r.failures.Load()
+}
+
+func (r *RetryWriter) retry(fn func() error) error {
+	err := fn()
+	for attempt := 0; err != nil && attempt < r.policy.MaxRetries; attempt++ {
+		if errors.Is(err, errorx.ErrBufferClose) {
+			break
+		}
+
+		time.Sleep(r.policy.delay(attempt))
+		r.attempts.Add(1)
+		err = fn()
+	}
+
+	if err != nil {
+		r.failures.Add(1)
+		return err
+	}
+
+	r.successes.Add(1)
+	return nil
+}
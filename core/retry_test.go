@@ -0,0 +1,123 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockWriter 前fails次写入只写入一个字节并返回err
+type mockWriter struct {
+	buf    bytes.Buffer
+	fails  int
+	err    error
+	writes int
+	closed bool
+}
+
+func (m *mockWriter) Write(p []byte) (int, error) {
+	m.writes++
+	if m.fails > 0 {
+		m.fails--
+		if len(p) == 0 {
+			return 0, m.err
+		}
+		m.buf.Write(p[:1])
+		return 1, m.err
+	}
+
+	return m.buf.Write(p)
+}
+
+func (m *mockWriter) WriteEntity(e Entity) error {
+	_, err := m.Write([]byte(e.Message))
+	return err
+}
+
+func (m *mockWriter) Flush() error {
+	if m.fails > 0 {
+		m.fails--
+		return m.err
+	}
+
+	return nil
+}
+
+func (m *mockWriter) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{InitialDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Multiplier: 2}
+	testCases := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: 10 * time.Millisecond},
+		{attempt: 1, max: 20 * time.Millisecond},
+		{attempt: 2, max: 40 * time.Millisecond},
+		{attempt: 3, max: 50 * time.Millisecond},
+		{attempt: 100, max: 50 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("attempt-%d", tc.attempt), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				d := p.delay(tc.attempt)
+				assert.GreaterOrEqual(t, d, tc.max/2)
+				assert.LessOrEqual(t, d, tc.max)
+			}
+		})
+	}
+}
+
+func TestRetryWriter(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond, Multiplier: 2}
+	mockErr := errors.New("mock error")
+
+	// 部分写入失败时只重试剩余的数据
+	base := &mockWriter{fails: 2, err: mockErr}
+	w := NewRetryWriter(base, policy)
+	n, err := w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, "hello", base.buf.String())
+	attempts, successes, failures := w.RetryStats()
+	assert.Equal(t, []int64{2, 1, 0}, []int64{attempts, successes, failures})
+
+	// 超过最大重试次数
+	base.fails = 10
+	assert.ErrorIs(t, w.Flush(), mockErr)
+	attempts, successes, failures = w.RetryStats()
+	assert.Equal(t, []int64{5, 1, 1}, []int64{attempts, successes, failures})
+
+	// 缓冲区关闭不重试
+	base = &mockWriter{fails: 10, err: errorx.ErrBufferClose}
+	w = NewRetryWriter(base, policy)
+	_, err = w.Write([]byte("hello"))
+	assert.ErrorIs(t, err, errorx.ErrBufferClose)
+	assert.Equal(t, 1, base.writes)
+
+	assert.NoError(t, w.Close())
+	assert.True(t, base.closed)
+}
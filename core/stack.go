@@ -15,6 +15,8 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -45,16 +47,63 @@ func WithSkip(skip int32) CallWrapOptions {
 	}
 }
 
+// WithMaxDepth 设置Fullnames返回的最大堆栈层数，与skip相互独立，skip只控制跳过的层数，
+// 不设置或者小于等于0时最大层数与skip相同
+func WithMaxDepth(n int) CallWrapOptions {
+	return func(w *CallEntityWrap) {
+		w.maxDepth.Store(int32(n))
+	}
+}
+
 func WithParts(parts int32) CallWrapOptions {
 	return func(w *CallEntityWrap) {
 		w.parts.Store(parts)
 	}
 }
 
+// WithGoroutineID 开启协程ID的采集，由于runtime.Stack存在内存分配，默认关闭
+func WithGoroutineID() CallWrapOptions {
+	return func(w *CallEntityWrap) {
+		w.enableGID.Store(true)
+	}
+}
+
 // funcNameCache 全局的方法与PC映射关系缓存，可以显著提高性能
 // 正常情况下方法的PC是不会变化的，动态插件例外。
 var funcNameCache sync.Map
 
+// FuncNameCacheLen 返回方法名缓存的条目数量
+func FuncNameCacheLen() int {
+	n := 0
+	funcNameCache.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+
+	return n
+}
+
+// FuncNameCachePurge 清空方法名缓存，用于测试之间重置状态或者重新加载动态插件之后
+func FuncNameCachePurge() {
+	funcNameCache.Range(func(key, _ any) bool {
+		funcNameCache.Delete(key)
+		return true
+	})
+}
+
+// FuncNameCachePurgeKey 删除指定PC的方法名缓存，用于卸载动态插件时选择性的失效
+func FuncNameCachePurgeKey(pc uintptr) {
+	funcNameCache.Delete(pc)
+}
+
+// debugMode 调试模式，开启后CallerEntity的JSON序列化结果中会包含pc指针
+var debugMode atomic.Bool
+
+// SetDebugMode 设置是否开启调试模式
+func SetDebugMode(enabled bool) {
+	debugMode.Store(enabled)
+}
+
 // callerEntityPool 堆栈实体对象池，减少每次调用堆栈时的对象创建开销和GC开销
 var callerEntityPool = sync.Pool{
 	New: func() interface{} {
@@ -62,13 +111,58 @@ var callerEntityPool = sync.Pool{
 	},
 }
 
+// stackBufSize 解析协程ID的缓冲区大小，只需要第一行"goroutine NNN [running]:"
+const stackBufSize = 128
+
+// stackBufPool 协程ID解析缓冲区对象池，分摊runtime.Stack的内存分配开销
+var stackBufPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, stackBufSize)
+		return &buf
+	},
+}
+
+// goroutinePrefix runtime.Stack输出的第一行前缀
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID 获取当前的协程ID，解析runtime.Stack第一行的"goroutine NNN [running]"
+// 解析失败时返回0
+func goroutineID() int64 {
+	bp, _ := stackBufPool.Get().(*[]byte)
+	defer stackBufPool.Put(bp)
+
+	buf := *bp
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, goroutinePrefix)
+	idx := bytes.IndexByte(buf, ' ')
+	if idx <= 0 {
+		return 0
+	}
+
+	id, err := strconv.ParseInt(string(buf[:idx]), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
+
 type CallEntityWrap struct {
 	// 是否启用函数方法打印
 	enablePC atomic.Bool
 	// 堆栈信息的级别，打印几级
 	skip atomic.Int32
+	// Fullnames返回的最大堆栈层数
+	maxDepth atomic.Int32
 	// 文件路径打印几部分
 	parts atomic.Int32
+	// 是否启用协程ID打印
+	enableGID atomic.Bool
+}
+
+// NewCallEntityWrap 创建堆栈信息获取实例
+func NewCallEntityWrap(opts ...CallWrapOptions) *CallEntityWrap {
+	return newCallEntityWrap(opts...)
 }
 
 func newCallEntityWrap(opts ...CallWrapOptions) *CallEntityWrap {
@@ -76,6 +170,7 @@ func newCallEntityWrap(opts ...CallWrapOptions) *CallEntityWrap {
 	cew.enablePC.Store(false)
 	cew.skip.Store(DefaultSkip)
 	cew.parts.Store(DefaultParts)
+	cew.enableGID.Store(false)
 
 	for _, opt := range opts {
 		opt(cew)
@@ -92,35 +187,87 @@ func (cw *CallEntityWrap) Fullname() string {
 	defer ce.release()
 
 	ce.caller(skip)
+	var res string
 	if cw.enablePC.Load() {
-		return ce.fullstrWithFunc(int(cw.parts.Load()))
+		res = ce.fullstrWithFunc(int(cw.parts.Load()))
+	} else {
+		res = ce.fullstr(int(cw.parts.Load()))
 	}
 
-	return ce.fullstr(int(cw.parts.Load()))
+	if cw.enableGID.Load() {
+		res += " goroutine:" + strconv.FormatInt(goroutineID(), 10)
+	}
+
+	return res
 }
 
 // Fullnames 获取多条完整的格式化堆栈信息，用于ErrorLevel、PanicLevel和FatalLevel
-// 多条的堆栈信息必须指定打印的指定级别，需要更多的还原错误异常现场，默认是打印3级别
+// 多条的堆栈信息必须指定打印的指定级别，需要更多的还原错误异常现场，默认是打印3级别，
+// 最多返回maxDepth条，实际的调用栈不足时只返回实际的层数
 func (cw *CallEntityWrap) Fullnames() []string {
 	ce := newCallerEntity()
 	defer ce.release()
 
-	cs, n := ce.callers(int(cw.skip.Load()))
-	var res []string
-	for i := 0; i < n; i++ {
-		pc := cs[i]
-		file, line, ok := ce.information(pc)
-		if !ok {
-			return nil
-		}
+	skip := int(cw.skip.Load())
+	depth := int(cw.maxDepth.Load())
+	if depth <= 0 {
+		depth = skip
+	}
+	cs, n := ce.callers(skip, depth)
+	if n == 0 {
+		return nil
+	}
 
-		ce.ok, ce.pc, ce.file, ce.line = ok, pc, file, line
+	// 使用CallersFrames展开内联的方法，FuncForPC会把内联方法所在的帧解析为被内联的方法，
+	// 导致封装层被内联时调用方的文件、行号和方法名称错位
+	frames := runtime.CallersFrames(cs[:n])
+	var res []string
+	for {
+		frame, more := frames.Next()
+		ce.ok, ce.pc, ce.file, ce.line = true, frame.PC, frame.File, frame.Line
 		if cw.enablePC.Load() {
-			res = append(res, ce.fullstrWithFunc(int(cw.parts.Load())))
+			res = append(res, ce.fullstrWithName(int(cw.parts.Load()), shortFuncName(frame.Function)))
 		} else {
 			res = append(res, ce.fullstr(int(cw.parts.Load())))
 		}
-		ce.release()
+		if !more {
+			break
+		}
+	}
+
+	return res
+}
+
+// OrignalEntities 获取多条堆栈的原始数据，用于ErrorLevel、PanicLevel和FatalLevel的结构化输出，
+// 跳过的层数和最大层数与Fullnames一致
+func (cw *CallEntityWrap) OrignalEntities() []CallerEntity {
+	ce := newCallerEntity()
+	defer ce.release()
+
+	skip := int(cw.skip.Load())
+	depth := int(cw.maxDepth.Load())
+	if depth <= 0 {
+		depth = skip
+	}
+	cs, n := ce.callers(skip, depth)
+	if n == 0 {
+		return nil
+	}
+
+	frames := runtime.CallersFrames(cs[:n])
+	res := make([]CallerEntity, 0, n)
+	for {
+		frame, more := frames.Next()
+		res = append(res, CallerEntity{
+			pc:       frame.PC,
+			file:     frame.File,
+			line:     frame.Line,
+			ok:       true,
+			function: frame.Function,
+		})
+		if !more {
+			break
+		}
 	}
 
 	return res
@@ -133,12 +280,17 @@ func (cw *CallEntityWrap) OrignalEntity() CallerEntity {
 
 	ce.caller(int(cw.skip.Load()))
 
-	return CallerEntity{
+	entity := CallerEntity{
 		pc:   ce.pc,
 		file: ce.file,
 		line: ce.line,
 		ok:   ce.ok,
 	}
+	if cw.enableGID.Load() {
+		entity.goroutineID = goroutineID()
+	}
+
+	return entity
 }
 
 // CEntity 堆栈调用实体
@@ -146,8 +298,13 @@ type CEntity struct {
 	CallerEntity
 	// 加锁保护
 	lock sync.Mutex
+	// 是否已经放回对象池，用于检测重复释放
+	released atomic.Bool
 }
 
+// errDoubleRelease 重复释放CEntity时panic的信息
+const errDoubleRelease = "logx: CEntity released twice"
+
 type CallerEntity struct {
 	// 指向调用的下一级函数
 	pc uintptr
@@ -157,26 +314,104 @@ type CallerEntity struct {
 	line int
 	// 是否成功获取调用的堆栈信息
 	ok bool
+	// 调用发生的协程ID，只有开启WithGoroutineID时才会采集
+	goroutineID int64
+	// CallersFrames展开的方法全名，内联的方法无法通过pc获取名称，为空时通过pc获取
+	function string
+}
+
+// NewCallerEntity 使用文件和行号构造堆栈信息，用于从序列化数据中还原，没有pc所以无法获取方法名称
+func NewCallerEntity(file string, line int) CallerEntity {
+	return CallerEntity{file: file, line: line, ok: true}
+}
+
+// File 调用发生的源文件
+func (c CallerEntity) File() string {
+	return c.file
+}
+
+// Line 调用发生的源文件行号
+func (c CallerEntity) Line() int {
+	return c.line
+}
+
+// Func 调用发生的方法名称，获取失败时为UNKNOWN
+func (c CallerEntity) Func() string {
+	return c.fname()
+}
+
+// GoroutineID 返回调用发生的协程ID，未开启采集时为0
+func (c CallerEntity) GoroutineID() int64 {
+	return c.goroutineID
+}
+
+// callerStringParts String输出的文件路径保留的层级
+const callerStringParts = 2
+
+// String 实现fmt.Stringer接口，输出"file:line"格式，比如"core/pool.go:42"，
+// 零值或者获取失败时输出UNKNOWN
+func (c CallerEntity) String() string {
+	if !c.ok {
+		return _const.Unknown
+	}
+
+	return c.getFile(callerStringParts) + ":" + strconv.Itoa(c.line)
+}
+
+// callerJSON CallerEntity的JSON序列化格式
+type callerJSON struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Func string `json:"func"`
+	PC   string `json:"pc,omitempty"`
+}
+
+// MarshalJSON 实现json.Marshaler接口，CallerEntity的字段均未导出，需要手动序列化，
+// 文件路径与fullstr保持一致只保留DefaultParts部分，只有调试模式下才输出pc
+func (c CallerEntity) MarshalJSON() ([]byte, error) {
+	if !c.ok {
+		return json.Marshal(callerJSON{File: _const.Unknown, Func: _const.Unknown})
+	}
+
+	cj := callerJSON{
+		File: c.getFile(DefaultParts),
+		Line: c.line,
+		Func: c.fname(),
+	}
+	if debugMode.Load() {
+		cj.PC = "0x" + strconv.FormatUint(uint64(c.pc), 16)
+	}
+
+	return json.Marshal(cj)
 }
 
 func newCallerEntity() *CEntity {
 	obj, _ := callerEntityPool.Get().(*CEntity)
+	obj.released.Store(false)
 	return obj
 }
 
-// release 释放对象
+// release 释放对象，重复释放会导致对象池中出现重复的引用，下一次获取时可能拿到正在使用的对象，
+// 所以检测到重复释放时直接panic
 func (c *CEntity) release() {
+	if !c.released.CompareAndSwap(false, true) {
+		panic(errDoubleRelease)
+	}
+
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	c.pc, c.file, c.line, c.ok = 0, "", 0, false
+	c.pc, c.file, c.line, c.ok, c.goroutineID, c.function = 0, "", 0, false, 0, ""
 	callerEntityPool.Put(c)
 }
 
 // fname 指针指向的方法名称
 // 预先从缓存中加载PC与名称，如果查询不到再解析名称，并缓存映射关系
-func (c *CEntity) fname() string {
-	if !c.ok {
+func (c CallerEntity) fname() string {
+	if c.ok && c.function != "" {
+		return shortFuncName(c.function)
+	}
+	if !c.ok || c.pc == 0 {
 		return _const.Unknown
 	}
 
@@ -186,16 +421,20 @@ func (c *CEntity) fname() string {
 		return fname
 	}
 
-	fn = runtime.FuncForPC(c.pc).Name()
-	fname, _ := fn.(string)
+	name := shortFuncName(runtime.FuncForPC(c.pc).Name())
+	funcNameCache.Store(c.pc, name)
+
+	return name
+}
+
+// shortFuncName 去除方法全名中的包路径和接收者，比如"github.com/TimeWtr/logx.(*Log).Error"返回"Error"
+func shortFuncName(fname string) string {
 	fnSli := strings.Split(fname, ".")
-	if len(fnSli) == 0 {
+	if fname == "" || len(fnSli) == 0 {
 		return _const.Unknown
 	}
-	name := fnSli[len(fnSli)-1]
-	funcNameCache.Store(c.pc, name)
 
-	return name
+	return fnSli[len(fnSli)-1]
 }
 
 // caller 捕获堆栈信息
@@ -223,25 +462,30 @@ func (c *CEntity) fullstr(parts int) string {
 
 // fullstrWithFunc 返回完整的字符串格式数据，不包括方法名
 func (c *CEntity) fullstrWithFunc(parts int) string {
+	return c.fullstrWithName(parts, c.fname())
+}
+
+// fullstrWithName 使用指定的方法名称返回完整的字符串格式数据，用于内联方法无法通过pc获取名称的场景
+func (c *CEntity) fullstrWithName(parts int, name string) string {
 	if !c.ok {
 		return "UNKNOWN"
 	}
 
 	var builder strings.Builder
-	builder.WriteString(c.fname())
+	builder.WriteString(name)
 	builder.WriteString(c.getFile(parts))
 	builder.WriteString(" line:")
 	builder.WriteString(strconv.Itoa(c.line))
 	builder.WriteString(" func:")
-	builder.WriteString(c.fname())
+	builder.WriteString(name)
 
 	return builder.String()
 }
 
-func (c *CEntity) getFile(parts int) string {
+func (c CallerEntity) getFile(parts int) string {
 	var file string
 	sli := strings.Split(c.file, string(os.PathSeparator))
-	if len(sli) == 0 {
+	if parts <= 0 || len(sli) <= parts {
 		file = c.file
 	} else {
 		file = filepath.Join(sli[len(sli)-parts:]...)
@@ -250,22 +494,12 @@ func (c *CEntity) getFile(parts int) string {
 	return file
 }
 
-// callers 捕获多级的堆栈信息
-func (c *CEntity) callers(skips int) (pcs []uintptr, cs int) {
-	pcs = make([]uintptr, skips)
+// callers 跳过skips层后最多获取depth层的调用栈，runtime.Callers的skip从自身开始计算，
+// 比runtime.Caller多一层，加1后skip的语义与caller保持一致
+func (c *CEntity) callers(skips, depth int) (pcs []uintptr, cs int) {
+	pcs = make([]uintptr, depth)
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	return pcs, runtime.Callers(skips, pcs)
-}
-
-// information 根据pc获取详细堆栈信息
-func (c *CEntity) information(pc uintptr) (file string, line int, ok bool) {
-	fn := runtime.FuncForPC(pc)
-	if fn == nil {
-		return "UNKNOWN", 0, false
-	}
-
-	file, line = fn.FileLine(pc)
-	return file, line, true
+	return pcs, runtime.Callers(skips+1, pcs)
 }
@@ -16,7 +16,13 @@ package core
 
 import (
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func m() string {
@@ -80,6 +86,100 @@ func TestCallEntityWrap_Fullnames(t *testing.T) {
 	}
 }
 
+func TestCallEntityWrap_FullnamesSkip(t *testing.T) {
+	// 默认的skip下，同一行调用的Fullname和Fullnames的第一帧是一致的
+	cew := newCallEntityWrap(WithMaxDepth(3))
+	single, multi := cew.Fullname(), cew.Fullnames()
+	assert.NotEmpty(t, multi)
+	assert.Equal(t, single, multi[0])
+	assert.Contains(t, single, "stack_test.go")
+
+	cew = newCallEntityWrap(WithPC(), WithMaxDepth(3))
+	multi = cew.Fullnames()
+	assert.Contains(t, multi[0], "TestCallEntityWrap_FullnamesSkip")
+}
+
+func TestFuncNameCache(t *testing.T) {
+	FuncNameCachePurge()
+	assert.Equal(t, 0, FuncNameCacheLen())
+
+	// 获取带方法名的堆栈信息时写入缓存
+	_ = newCallEntityWrap(WithPC()).Fullname()
+	assert.Equal(t, 1, FuncNameCacheLen())
+
+	funcNameCache.Store(uintptr(1), "plugin.Foo")
+	funcNameCache.Store(uintptr(2), "plugin.Bar")
+	assert.Equal(t, 3, FuncNameCacheLen())
+	FuncNameCachePurgeKey(1)
+	assert.Equal(t, 2, FuncNameCacheLen())
+	_, ok := funcNameCache.Load(uintptr(2))
+	assert.True(t, ok)
+
+	FuncNameCachePurge()
+	assert.Equal(t, 0, FuncNameCacheLen())
+}
+
+func TestCEntity_DoubleRelease(t *testing.T) {
+	assert.PanicsWithValue(t, errDoubleRelease, func() {
+		ce := newCallerEntity()
+		defer ce.release()
+		ce.release()
+	})
+
+	// 重新从对象池中获取之后可以正常释放
+	ce := newCallerEntity()
+	assert.NotPanics(t, ce.release)
+}
+
+func TestCallEntityWrap_MaxDepth(t *testing.T) {
+	// 调用链：callers -> Fullnames -> 调用方
+	cew := newCallEntityWrap(WithSkip(2), WithMaxDepth(1))
+	frames := cew.Fullnames()
+	assert.Len(t, frames, 1)
+	assert.Contains(t, frames[0], "stack_test.go")
+
+	// 实际的调用栈不足maxDepth时只返回实际的层数
+	cew = newCallEntityWrap(WithSkip(2), WithMaxDepth(100))
+	frames = cew.Fullnames()
+	assert.Greater(t, len(frames), 1)
+	assert.Less(t, len(frames), 100)
+	assert.Contains(t, frames[0], "stack_test.go")
+
+	// 不设置maxDepth时与skip相同
+	cew = newCallEntityWrap(WithSkip(2))
+	assert.LessOrEqual(t, len(cew.Fullnames()), 2)
+}
+
+func TestCallEntityWrap_GoroutineID(t *testing.T) {
+	cew := newCallEntityWrap(WithGoroutineID())
+	var wg sync.WaitGroup
+	ids := make([]int64, 10)
+	for i := 0; i < len(ids); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			ids[i] = goroutineID()
+			name := cew.Fullname()
+			assert.True(t, strings.HasSuffix(name, " goroutine:"+strconv.FormatInt(ids[i], 10)))
+			assert.Equal(t, ids[i], cew.OrignalEntity().GoroutineID())
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		assert.Greater(t, id, int64(0))
+		seen[id] = struct{}{}
+	}
+	assert.Len(t, seen, len(ids))
+
+	// 默认关闭时不输出协程ID
+	cew = newCallEntityWrap()
+	assert.NotContains(t, cew.Fullname(), "goroutine:")
+	assert.Equal(t, int64(0), cew.OrignalEntity().GoroutineID())
+}
+
 func BenchmarkCallEntityWrap_Fullnames_NotPC(b *testing.B) {
 	cew := newCallEntityWrap(WithSkip(5), WithPC(), WithParts(2))
 	for i := 0; i < 10000; i++ {
@@ -93,3 +193,25 @@ func BenchmarkCallEntityWrap_Fullnames_PC(b *testing.B) {
 		b.Logf("fullename: %s", cew.Fullnames())
 	}
 }
+
+func TestCallerEntity_String(t *testing.T) {
+	assert.Equal(t, "UNKNOWN", CallerEntity{}.String())
+	assert.Equal(t, "UNKNOWN", fmt.Sprintf("%v", CallerEntity{}))
+
+	ce := CallerEntity{file: filepath.Join("root", "logx", "core", "pool.go"), line: 42, ok: true}
+	assert.Equal(t, filepath.Join("core", "pool.go")+":42", ce.String())
+	assert.Equal(t, ce.String(), fmt.Sprintf("%v", ce))
+	assert.Equal(t, "main.go:1", CallerEntity{file: "main.go", line: 1, ok: true}.String())
+
+	entity := newCallEntityWrap().OrignalEntity()
+	assert.Contains(t, entity.String(), "stack_test.go:")
+}
+
+func TestNewCallerEntity(t *testing.T) {
+	ce := NewCallerEntity(filepath.Join("logx", "core", "pool.go"), 42)
+	assert.Equal(t, filepath.Join("logx", "core", "pool.go"), ce.File())
+	assert.Equal(t, 42, ce.Line())
+	assert.Equal(t, filepath.Join("core", "pool.go")+":42", ce.String())
+	// 没有pc无法获取方法名称
+	assert.Equal(t, "UNKNOWN", ce.Func())
+}
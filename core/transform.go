@@ -0,0 +1,105 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "maps"
+
+// TransformFunc 修改日志数据的函数，在写入的过程中同步执行，需要保证足够快
+type TransformFunc func(e *Entity)
+
+// ChainTransforms 组合多个TransformFunc，按照顺序依次执行，为空的函数会被忽略
+func ChainTransforms(fns ...TransformFunc) TransformFunc {
+	return func(e *Entity) {
+		for _, fn := range fns {
+			if fn != nil {
+				fn(e)
+			}
+		}
+	}
+}
+
+// AddField 添加结构化字段，已经存在时覆盖
+func AddField(key, value string) TransformFunc {
+	return func(e *Entity) {
+		if e.Fields == nil {
+			e.Fields = make(map[string]any)
+		}
+		e.Fields[key] = value
+	}
+}
+
+// SetService 设置服务名称
+func SetService(name string) TransformFunc {
+	return func(e *Entity) {
+		e.Service = name
+	}
+}
+
+// MaskField 使用mask替换结构化字段的值，字段不存在时不做处理
+func MaskField(key, mask string) TransformFunc {
+	return func(e *Entity) {
+		if _, ok := e.Fields[key]; ok {
+			e.Fields[key] = mask
+		}
+	}
+}
+
+// LevelMapper 按照mapping转换日志级别，比如兼容只支持部分级别的接收端，未配置的级别不做转换
+func LevelMapper(mapping map[LoggerLevel]LoggerLevel) TransformFunc {
+	return func(e *Entity) {
+		if level, ok := mapping[e.Level]; ok {
+			e.Level = level
+		}
+	}
+}
+
+// TransformWriter 写入前修改日志数据的装饰器，比如添加服务标签、字段脱敏等。通过Log写入时Message
+// 已经格式化完成，修改只作用于Fields、Service等结构化的数据，由Loki、Fluentd等结构化的写入器输出
+type TransformWriter struct {
+	// 被装饰的写入器
+	base Writer
+	// 修改日志数据的函数
+	fn TransformFunc
+}
+
+func NewTransformWriter(base Writer, fn TransformFunc) *TransformWriter {
+	return &TransformWriter{
+		base: base,
+		fn:   fn,
+	}
+}
+
+// Write 字节数据无法修改，直接写入
+func (t *TransformWriter) Write(p []byte) (n int, err error) {
+	return t.base.Write(p)
+}
+
+// WriteEntity 复制结构化字段后再执行修改，避免影响调用方和其他写入器的数据
+func (t *TransformWriter) WriteEntity(e Entity) error {
+	if t.fn != nil {
+		e.Fields = maps.Clone(e.Fields)
+		t.fn(&e)
+	}
+
+	return t.base.WriteEntity(e)
+}
+
+func (t *TransformWriter) Flush() error {
+	return t.base.Flush()
+}
+
+func (t *TransformWriter) Close() error {
+	return t.base.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// entityWriter 记录写入的结构化日志数据
+type entityWriter struct {
+	mockWriter
+	entities []Entity
+}
+
+func (w *entityWriter) WriteEntity(e Entity) error {
+	w.entities = append(w.entities, e)
+	return nil
+}
+
+func TestTransformWriter(t *testing.T) {
+	base := &entityWriter{}
+	w := NewTransformWriter(base, ChainTransforms(
+		AddField("env", "prod"),
+		SetService("api"),
+		MaskField("host", "***"),
+		MaskField("missing", "***"),
+		nil,
+		LevelMapper(map[LoggerLevel]LoggerLevel{PanicLevel: ErrorLevel}),
+	))
+
+	fields := map[string]any{"host": "10.0.0.1"}
+	assert.NoError(t, w.WriteEntity(Entity{Level: PanicLevel, Message: "hello", Fields: fields}))
+	assert.NoError(t, w.WriteEntity(Entity{Level: InfoLevel}))
+	assert.Len(t, base.entities, 2)
+
+	e := base.entities[0]
+	assert.Equal(t, ErrorLevel, e.Level)
+	assert.Equal(t, "api", e.Service)
+	assert.Equal(t, map[string]any{"env": "prod", "host": "***"}, e.Fields)
+	// 调用方的字段不会被修改
+	assert.Equal(t, map[string]any{"host": "10.0.0.1"}, fields)
+
+	e = base.entities[1]
+	assert.Equal(t, InfoLevel, e.Level)
+	assert.Equal(t, map[string]any{"env": "prod"}, e.Fields)
+
+	// 字节数据直接写入
+	_, err := w.Write([]byte("raw"))
+	assert.NoError(t, err)
+	assert.Equal(t, "raw", base.buf.String())
+	assert.NoError(t, w.Flush())
+	assert.NoError(t, w.Close())
+	assert.True(t, base.closed)
+}
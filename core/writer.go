@@ -15,7 +15,17 @@
 package core
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
 )
 
 //// Writer 写入器抽象接口
@@ -40,7 +50,7 @@ import (
 
 // Entity 结构化日志数据格式
 type Entity struct {
-	// 日志时间戳
+	// 日志时间戳，Unix纳秒
 	Timestamp int64
 	// 日志级别
 	Level LoggerLevel
@@ -56,36 +66,281 @@ type Entity struct {
 	CE []CallerEntity
 }
 
+const (
+	// pooledEntityFields 对象池中Entity预分配的字段数量
+	pooledEntityFields = 4
+	// pooledEntityFrames 对象池中Entity预分配的堆栈数量
+	pooledEntityFrames = 3
+)
+
+// entityPool Entity对象池，减少每条日志创建Entity、Fields和CE的内存分配
+var entityPool = sync.Pool{
+	New: func() interface{} {
+		return &Entity{
+			Fields: make(map[string]any, pooledEntityFields),
+			CE:     make([]CallerEntity, 0, pooledEntityFrames),
+		}
+	},
+}
+
+// GetEntity 从对象池中获取空的Entity，Fields和CE已经预分配，使用完成后调用PutEntity放回
+func GetEntity() *Entity {
+	e, _ := entityPool.Get().(*Entity)
+	return e
+}
+
+// PutEntity 清空Entity的内容后放回对象池，Fields和CE保留容量，放回之后不能继续使用
+// e以及e.Fields、e.CE，异步持有Entity的场景不能放回对象池
+func PutEntity(e *Entity) {
+	if e == nil {
+		return
+	}
+
+	fields, ce := e.Fields, e.CE
+	clear(fields)
+	// 清空堆栈数据，避免对象池持有过期的引用
+	clear(ce)
+	*e = Entity{Fields: fields, CE: ce[:0]}
+	entityPool.Put(e)
+}
+
+// EntityBuilder Entity的构造器，用于测试和自定义写入器中构造Entity，Build返回的Entity
+// 不会和构造器共享Fields和CE，构造器可以调用Reset后复用，非并发安全
+type EntityBuilder struct {
+	e Entity
+}
+
+// entityBuilderPool 构造器对象池
+var entityBuilderPool = sync.Pool{
+	New: func() interface{} {
+		return NewEntityBuilder()
+	},
+}
+
+func NewEntityBuilder() *EntityBuilder {
+	return &EntityBuilder{}
+}
+
+// GetEntityBuilder 从对象池中获取空的构造器，使用完成后调用PutEntityBuilder放回
+func GetEntityBuilder() *EntityBuilder {
+	b, _ := entityBuilderPool.Get().(*EntityBuilder)
+	return b
+}
+
+// PutEntityBuilder 重置构造器后放回对象池
+func PutEntityBuilder(b *EntityBuilder) {
+	if b == nil {
+		return
+	}
+
+	b.Reset()
+	entityBuilderPool.Put(b)
+}
+
+func (b *EntityBuilder) WithTimestamp(t time.Time) *EntityBuilder {
+	b.e.Timestamp = t.UnixNano()
+	return b
+}
+
+func (b *EntityBuilder) WithLevel(l LoggerLevel) *EntityBuilder {
+	b.e.Level = l
+	return b
+}
+
+func (b *EntityBuilder) WithTraceID(id string) *EntityBuilder {
+	b.e.TraceID = id
+	return b
+}
+
+func (b *EntityBuilder) WithService(name string) *EntityBuilder {
+	b.e.Service = name
+	return b
+}
+
+func (b *EntityBuilder) WithMessage(msg string) *EntityBuilder {
+	b.e.Message = msg
+	return b
+}
+
+// WithField 添加结构化字段，key重复时后者覆盖前者
+func (b *EntityBuilder) WithField(key string, val any) *EntityBuilder {
+	if b.e.Fields == nil {
+		b.e.Fields = make(map[string]any)
+	}
+	b.e.Fields[key] = val
+	return b
+}
+
+func (b *EntityBuilder) WithCallerEntity(ce CallerEntity) *EntityBuilder {
+	b.e.CE = append(b.e.CE, ce)
+	return b
+}
+
+// Build 校验并返回构造的Entity，日志级别不合法或者消息为空时返回*errorx.ConfigError
+func (b *EntityBuilder) Build() (Entity, error) {
+	if !b.e.Level.Valid() {
+		return Entity{}, &errorx.ConfigError{Field: "level", Reason: fmt.Sprintf("invalid entity level %d", b.e.Level)}
+	}
+	if b.e.Message == "" {
+		return Entity{}, &errorx.ConfigError{Field: "message", Reason: "can't be empty"}
+	}
+
+	e := b.e
+	e.Fields = maps.Clone(b.e.Fields)
+	e.CE = slices.Clone(b.e.CE)
+	return e, nil
+}
+
+// Reset 清空构造器，Fields和CE保留容量
+func (b *EntityBuilder) Reset() {
+	fields, ce := b.e.Fields, b.e.CE
+	clear(fields)
+	clear(ce)
+	b.e = Entity{Fields: fields, CE: ce[:0]}
+}
+
 // Writer 定义抽象的Writer接口，支持文件、网络、终端和消息队列(Kafka)的写入/输出
 type Writer interface {
 	io.Writer
+	// WriteEntity 写入结构化的日志数据，装饰器可以根据日志级别等信息做处理。Log通过WriteEntity
+	// 写入运行时添加的写入器，Message为原始消息，不包括时间、级别和字段，字段保留在Fields中。
+	// e.Fields和e.CE在返回之后会被复用，需要异步持有时先拷贝
+	WriteEntity(e Entity) error
 	// Flush 刷新缓冲区(文件、网络)
 	Flush() error
 	// Close 释放资源
 	Close() error
 }
 
+// Rotator 支持主动轮转的写入器，比如收到SIGHUP信号后重新打开日志文件
+type Rotator interface {
+	Rotate() error
+}
+
+// FileWriter 文件写入器，OpenFileWriter打开的文件支持Rotate重新打开，配合logrotate等外部工具
+// 移走日志文件后继续写入新的文件，并发安全
 type FileWriter struct {
+	// 并发保护，Rotate时替换w
+	lock sync.Mutex
+	// 底层的写入器
 	w io.Writer
+	// 日志文件的路径，为空时不支持重新打开
+	path string
+	// WriteEntity使用的格式化器，默认为logfmt格式
+	formatter BinaryFormatter
 }
 
-func NewFileWriter(w io.Writer) Writer {
-	return &FileWriter{
-		w: w,
+// FileWriterOptions FileWriter的配置选项
+type FileWriterOptions func(*FileWriter)
+
+// WithFileFormatter 设置WriteEntity使用的格式化器，不设置时使用logfmt格式化器
+func WithFileFormatter(formatter BinaryFormatter) FileWriterOptions {
+	return func(f *FileWriter) {
+		f.formatter = formatter
 	}
 }
 
-func (f *FileWriter) Write(_ []byte) (n int, err error) {
-	// TODO implement me
-	panic("implement me")
+// NewFileWriter 包装已经打开的文件或者其他io.Writer，Flush和Close分别在w支持Sync和Close时调用
+func NewFileWriter(w io.Writer, opts ...FileWriterOptions) Writer {
+	return newFileWriter(w, "", opts...)
+}
+
+// OpenFileWriter 以追加的方式打开日志文件，目录不存在时自动创建
+func OpenFileWriter(path string, opts ...FileWriterOptions) (*FileWriter, error) {
+	f, err := openLogFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFileWriter(f, path, opts...), nil
 }
 
+func newFileWriter(w io.Writer, path string, opts ...FileWriterOptions) *FileWriter {
+	fw := &FileWriter{
+		w:         w,
+		path:      path,
+		formatter: NewLogfmtFormatter(),
+	}
+	for _, opt := range opts {
+		opt(fw)
+	}
+
+	return fw
+}
+
+func openLogFile(path string) (*os.File, error) {
+	const (
+		dirPerm  = 0o755
+		filePerm = 0o644
+	)
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+}
+
+func (f *FileWriter) Write(p []byte) (n int, err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.w.Write(p)
+}
+
+// WriteEntity 使用格式化器格式化后写入，每条日志占一行，没有以换行符结尾时追加换行符
+func (f *FileWriter) WriteEntity(e Entity) error {
+	data := f.formatter.Format(e)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		data = append(data, '\n')
+	}
+	_, err := f.Write(data)
+	return err
+}
+
+// Flush 底层的写入器支持Sync时把数据同步到磁盘
 func (f *FileWriter) Flush() error {
-	// TODO implement me
-	panic("implement me")
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if s, ok := f.w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+
+	return nil
 }
 
+// Close 底层的写入器支持Close时关闭
 func (f *FileWriter) Close() error {
-	// TODO implement me
-	panic("implement me")
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if c, ok := f.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// Rotate 重新打开日志文件，外部工具移走原来的文件后，后续的日志写入新创建的文件，
+// 不是通过OpenFileWriter打开时直接返回nil
+func (f *FileWriter) Rotate() error {
+	if f.path == "" {
+		return nil
+	}
+
+	file, err := openLogFile(f.path)
+	if err != nil {
+		return err
+	}
+
+	f.lock.Lock()
+	old := f.w
+	f.w = file
+	f.lock.Unlock()
+
+	if c, ok := old.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
 }
@@ -0,0 +1,171 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntity_MarshalJSON(t *testing.T) {
+	cew := newCallEntityWrap()
+	e := Entity{
+		Timestamp: time.Now().Unix(),
+		Level:     ErrorLevel,
+		Message:   "test message",
+		CE:        []CallerEntity{cew.OrignalEntity(), {}},
+	}
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+	t.Log(string(data))
+
+	var res struct {
+		CE []map[string]any
+	}
+	assert.NoError(t, json.Unmarshal(data, &res))
+	assert.Len(t, res.CE, 2)
+	assert.True(t, strings.HasSuffix(res.CE[0]["file"].(string), "writer_test.go"))
+	assert.Greater(t, res.CE[0]["line"], float64(0))
+	assert.NotEmpty(t, res.CE[0]["func"])
+	assert.NotContains(t, res.CE[0], "pc")
+	assert.Equal(t, "UNKNOWN", res.CE[1]["file"])
+
+	SetDebugMode(true)
+	defer SetDebugMode(false)
+	data, err = json.Marshal(e.CE[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"pc":"0x`)
+}
+
+func TestEntityPool(t *testing.T) {
+	e := GetEntity()
+	assert.NotNil(t, e.Fields)
+	assert.Equal(t, 0, len(e.CE))
+	e.Timestamp = 1
+	e.Level = ErrorLevel
+	e.Message = "error message"
+	e.Service = "logx"
+	e.Fields["key"] = "value"
+	e.CE = append(e.CE, CallerEntity{file: "main.go", line: 1, ok: true})
+	fields, ce := e.Fields, e.CE
+	PutEntity(e)
+	PutEntity(nil)
+
+	// 放回对象池时清空所有的内容并保留容量
+	assert.Equal(t, Entity{Fields: fields, CE: ce[:0]}, *e)
+	assert.Empty(t, fields)
+	assert.Equal(t, CallerEntity{}, ce[:1][0])
+	assert.Equal(t, pooledEntityFrames, cap(e.CE))
+}
+
+func TestEntityBuilder(t *testing.T) {
+	ts := time.Unix(1, 0)
+	ce := CallerEntity{file: "main.go", line: 1, ok: true}
+	b := NewEntityBuilder().
+		WithTimestamp(ts).
+		WithLevel(InfoLevel).
+		WithTraceID("trace-1").
+		WithService("logx").
+		WithMessage("info message").
+		WithField("key", "value").
+		WithCallerEntity(ce)
+	e, err := b.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, Entity{
+		Timestamp: ts.UnixNano(),
+		Level:     InfoLevel,
+		TraceID:   "trace-1",
+		Service:   "logx",
+		Message:   "info message",
+		Fields:    map[string]any{"key": "value"},
+		CE:        []CallerEntity{ce},
+	}, e)
+
+	// 构造完成的Entity和构造器不共享数据
+	b.WithField("key", "changed").WithField("other", 1)
+	assert.Equal(t, map[string]any{"key": "value"}, e.Fields)
+
+	b.Reset()
+	_, err = b.Build()
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+	_, err = b.WithLevel(ErrorLevel).Build()
+	assert.ErrorIs(t, err, errorx.ErrConfigInvalid)
+	e, err = b.WithMessage("error message").Build()
+	assert.NoError(t, err)
+	assert.Empty(t, e.Fields)
+	assert.Empty(t, e.CE)
+
+	pb := GetEntityBuilder()
+	e, err = pb.WithLevel(WarnLevel).WithMessage("warn message").Build()
+	assert.NoError(t, err)
+	assert.Equal(t, WarnLevel, e.Level)
+	PutEntityBuilder(pb)
+	PutEntityBuilder(nil)
+}
+
+func TestFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	w, err := OpenFileWriter(path)
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte("first\n"))
+	assert.NoError(t, err)
+	second := Entity{Level: InfoLevel, Message: "second"}
+	assert.NoError(t, w.WriteEntity(second))
+	assert.NoError(t, w.Flush())
+
+	// 模拟logrotate移走日志文件，Rotate之后写入新创建的文件
+	rotated := path + ".1"
+	assert.NoError(t, os.Rename(path, rotated))
+	_, err = w.Write([]byte("before rotate\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Rotate())
+	_, err = w.Write([]byte("after rotate\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	data, err := os.ReadFile(rotated)
+	assert.NoError(t, err)
+	// WriteEntity默认使用logfmt格式化器
+	assert.Equal(t, "first\n"+string(NewLogfmtFormatter().Format(second))+"\nbefore rotate\n", string(data))
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "after rotate\n", string(data))
+
+	// 包装的io.Writer不支持Sync、Close和重新打开
+	var buf bytes.Buffer
+	bw := NewFileWriter(&buf)
+	_, err = bw.Write([]byte("buffer"))
+	assert.NoError(t, err)
+	assert.NoError(t, bw.Flush())
+	assert.NoError(t, bw.(Rotator).Rotate())
+	assert.NoError(t, bw.Close())
+	assert.Equal(t, "buffer", buf.String())
+
+	buf.Reset()
+	gw := NewFileWriter(&buf, WithFileFormatter(NewGELFFormatter()))
+	assert.NoError(t, gw.WriteEntity(Entity{Level: InfoLevel, Message: "gelf entity"}))
+	assert.Contains(t, buf.String(), `"short_message":"gelf entity"`)
+	assert.True(t, strings.HasSuffix(buf.String(), "\n"))
+}
@@ -27,3 +27,47 @@ var (
 	ErrPoolEmpty   = errors.New("pool returned empty object")
 	ErrPoolMaxSize = errors.New("pool object over max size")
 )
+
+var (
+	ErrLevelInvalid  = errors.New("invalid logger level")
+	ErrLevelConflict = errors.New("logger level conflict")
+)
+
+var ErrMessageTooLarge = errors.New("message is too large")
+
+// ConfigError 配置不合法的错误，Field为不合法的配置项，Reason为不合法的原因，
+// 可以通过errors.As获取详细信息，所有的ConfigError都匹配errors.Is(err, ErrConfigInvalid)
+type ConfigError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	switch {
+	case e.Field == "" && e.Reason == "":
+		return "invalid config"
+	case e.Field == "":
+		return "invalid config: " + e.Reason
+	default:
+		return "invalid config: " + e.Field + ": " + e.Reason
+	}
+}
+
+// Is 所有的ConfigError都视为ErrConfigInvalid
+func (e *ConfigError) Is(target error) bool {
+	return target == ErrConfigInvalid
+}
+
+var ErrConfigInvalid error = &ConfigError{}
+
+// ErrEntrySuppressed Hook返回该错误时，日志不会被写入
+var ErrEntrySuppressed = errors.New("log entry suppressed")
+
+// ErrCircuitOpen 熔断器打开时直接返回该错误，不会执行真正的写入
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// ErrDuplicateField 日志的结构化字段中存在重复的key
+var ErrDuplicateField = errors.New("duplicate field")
+
+// ErrRotateFailed 日志轮转失败
+var ErrRotateFailed = errors.New("rotate failed")
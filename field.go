@@ -14,6 +14,18 @@
 
 package logx
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+)
+
 // FType 字段类型
 type FType uint8
 
@@ -36,8 +48,13 @@ const (
 	FloatTypeField
 	// DatetimeTypeField 时间格式的字段类型
 	DatetimeTypeField
+	// NilTypeField 空值的字段类型
+	NilTypeField
 )
 
+// errorFieldKey ErrorField使用的字段名
+const errorFieldKey = "error"
+
 type Field struct {
 	// 存储的字段名
 	Key string
@@ -46,3 +63,179 @@ type Field struct {
 	// 存储的复杂对象
 	Value any
 }
+
+// StringField 构造字符串类型的字段
+func StringField(key, val string) Field {
+	return Field{Key: key, Type: StringTypeField, Value: val}
+}
+
+// IntField 构造数值类型的字段
+func IntField(key string, val int64) Field {
+	return Field{Key: key, Type: IntTypeField, Value: val}
+}
+
+// FloatField 构造浮点类型的字段
+func FloatField(key string, val float64) Field {
+	return Field{Key: key, Type: FloatTypeField, Value: val}
+}
+
+// BoolField 构造布尔类型的字段
+func BoolField(key string, val bool) Field {
+	return Field{Key: key, Type: BoolTypeField, Value: val}
+}
+
+// TimeField 构造时间类型的字段
+func TimeField(key string, t time.Time) Field {
+	return Field{Key: key, Type: DatetimeTypeField, Value: t}
+}
+
+// ErrorField 构造key为error的字符串类型字段，err为空时返回空值字段
+func ErrorField(err error) Field {
+	if err == nil {
+		return NilField(errorFieldKey)
+	}
+
+	return Field{Key: errorFieldKey, Type: StringTypeField, Value: err.Error()}
+}
+
+// NilField 构造显式的空值字段
+func NilField(key string) Field {
+	return Field{Key: key, Type: NilTypeField}
+}
+
+// extraFieldKey 键值对的数量为奇数时，最后一个落单的值使用的字段名
+const extraFieldKey = "EXTRA"
+
+// sweetenFields 把交替出现的key、value转换为Field，key不是字符串时使用fmt.Sprint转换，
+// 最后一个落单的value使用EXTRA作为key
+func sweetenFields(keysAndValues []any) []Field {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, 0, (len(keysAndValues)+1)/2)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i == len(keysAndValues)-1 {
+			fields = append(fields, newField(extraFieldKey, keysAndValues[i]))
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields = append(fields, newField(key, keysAndValues[i+1]))
+	}
+
+	return fields
+}
+
+// newField 按照value的类型推断字段类型，error转换为字符串类型的字段
+func newField(key string, value any) Field {
+	f := Field{Key: key, Type: ObjectTypeField, Value: value}
+	switch v := value.(type) {
+	case nil:
+		f.Type = NilTypeField
+	case string:
+		f.Type = StringTypeField
+	case error:
+		f.Type, f.Value = StringTypeField, v.Error()
+	case bool:
+		f.Type = BoolTypeField
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		f.Type = IntTypeField
+	case float32, float64:
+		f.Type = FloatTypeField
+	case time.Time:
+		f.Type = DatetimeTypeField
+	case json.RawMessage:
+		f.Type = JSONTypeField
+	case []byte:
+		f.Type = BinaryTypeField
+	case []any, []string, []int, []int64, []float64, []bool:
+		f.Type = ArrTypeField
+	}
+
+	return f
+}
+
+// String 字段值的字符串格式，时间使用RFC3339Nano格式，二进制数据使用base64编码
+func (f Field) String() string {
+	switch v := f.Value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case json.RawMessage:
+		return string(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// ValidateFields 校验字段中是否存在重复的key，存在时返回errorx.ErrDuplicateField，
+// 用于在开发阶段发现问题，写入日志时重复的key会被重命名，不会丢弃
+func ValidateFields(fields []Field) error {
+	keys := make(map[string]struct{}, len(fields))
+	for _, f := range fields {
+		if _, ok := keys[f.Key]; ok {
+			return fmt.Errorf("%w: %q", errorx.ErrDuplicateField, f.Key)
+		}
+		keys[f.Key] = struct{}{}
+	}
+
+	return nil
+}
+
+// putFields 把字段写入map，key重复时重命名为key_dup_N，N从1开始递增
+func putFields(m map[string]any, fields []Field) {
+	for _, f := range fields {
+		key := f.Key
+		for n := 1; ; n++ {
+			if _, ok := m[key]; !ok {
+				break
+			}
+			key = f.Key + "_dup_" + strconv.Itoa(n)
+		}
+		m[key] = f.Value
+	}
+}
+
+// errorFields 把错误转换为字段，实现了Unwrap() []error的组合错误按照顺序转换为error.0、error.1等字段
+func errorFields(err error) []Field {
+	if err == nil {
+		return nil
+	}
+
+	multi, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []Field{ErrorField(err)}
+	}
+
+	errs := multi.Unwrap()
+	fields := make([]Field, 0, len(errs))
+	for i, e := range errs {
+		fields = append(fields, newField(errorFieldKey+"."+strconv.Itoa(i), e))
+	}
+
+	return fields
+}
+
+// encodeFields 按照顺序把字段编码为" key=value"格式，value按照core.WriteLogfmtValue的规则编码
+func encodeFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	for _, f := range fields {
+		builder.WriteByte(' ')
+		builder.WriteString(f.Key)
+		builder.WriteByte('=')
+		core.WriteLogfmtValue(&builder, f.String())
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweetenFields(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := sweetenFields([]any{
+		"name", "logx",
+		"count", 3,
+		"ratio", 0.5,
+		"ok", true,
+		"time", ts,
+		"err", errors.New("mock error"),
+		1, "int key",
+		"dangling",
+	})
+
+	assert.Equal(t, []Field{
+		{Key: "name", Type: StringTypeField, Value: "logx"},
+		{Key: "count", Type: IntTypeField, Value: 3},
+		{Key: "ratio", Type: FloatTypeField, Value: 0.5},
+		{Key: "ok", Type: BoolTypeField, Value: true},
+		{Key: "time", Type: DatetimeTypeField, Value: ts},
+		{Key: "err", Type: StringTypeField, Value: "mock error"},
+		{Key: "1", Type: StringTypeField, Value: "int key"},
+		{Key: extraFieldKey, Type: StringTypeField, Value: "dangling"},
+	}, fields)
+	assert.Nil(t, sweetenFields(nil))
+
+	assert.Equal(t, ` name=logx count=3 ratio=0.5 ok=true time=2025-01-02T03:04:05Z err="mock error" 1="int key" EXTRA=dangling`,
+		encodeFields(fields))
+	assert.Equal(t, ` empty=""`, encodeFields(sweetenFields([]any{"empty", ""})))
+	assert.Equal(t, "", encodeFields(nil))
+}
+
+func TestFieldConstructors(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		field Field
+		want  Field
+	}{
+		{
+			name:  "字符串",
+			field: StringField("name", "logx"),
+			want:  Field{Key: "name", Type: StringTypeField, Value: "logx"},
+		},
+		{
+			name:  "数值",
+			field: IntField("count", 3),
+			want:  Field{Key: "count", Type: IntTypeField, Value: int64(3)},
+		},
+		{
+			name:  "浮点",
+			field: FloatField("ratio", 0.5),
+			want:  Field{Key: "ratio", Type: FloatTypeField, Value: 0.5},
+		},
+		{
+			name:  "布尔",
+			field: BoolField("ok", true),
+			want:  Field{Key: "ok", Type: BoolTypeField, Value: true},
+		},
+		{
+			name:  "时间",
+			field: TimeField("time", ts),
+			want:  Field{Key: "time", Type: DatetimeTypeField, Value: ts},
+		},
+		{
+			name:  "错误",
+			field: ErrorField(errors.New("mock error")),
+			want:  Field{Key: "error", Type: StringTypeField, Value: "mock error"},
+		},
+		{
+			name:  "空错误",
+			field: ErrorField(nil),
+			want:  Field{Key: "error", Type: NilTypeField},
+		},
+		{
+			name:  "空值",
+			field: NilField("empty"),
+			want:  Field{Key: "empty", Type: NilTypeField},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.field)
+		})
+	}
+
+	assert.Equal(t, NilField("empty"), newField("empty", nil))
+	assert.Equal(t, ` time=2025-01-02T03:04:05Z error=<nil>`, encodeFields([]Field{TimeField("time", ts), ErrorField(nil)}))
+}
+
+func TestValidateFields(t *testing.T) {
+	assert.NoError(t, ValidateFields(nil))
+	assert.NoError(t, ValidateFields([]Field{StringField("a", "1"), StringField("b", "2")}))
+	err := ValidateFields([]Field{ErrorField(errors.New("e1")), StringField("b", "2"), ErrorField(errors.New("e2"))})
+	assert.ErrorIs(t, err, errorx.ErrDuplicateField)
+	assert.Contains(t, err.Error(), `"error"`)
+
+	m := map[string]any{}
+	putFields(m, []Field{
+		StringField("error", "e1"),
+		StringField("error", "e2"),
+		StringField("error_dup_1", "e3"),
+		StringField("error", "e4"),
+	})
+	assert.Equal(t, map[string]any{
+		"error":             "e1",
+		"error_dup_1":       "e2",
+		"error_dup_1_dup_1": "e3",
+		"error_dup_2":       "e4",
+	}, m)
+}
@@ -5,6 +5,8 @@ go 1.23.4
 require (
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/sync v0.13.0
+	golang.org/x/sys v0.33.0
+	golang.org/x/time v0.9.0
 )
 
 require (
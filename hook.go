@@ -0,0 +1,129 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+)
+
+// Hook 日志处理的扩展插件，可以用于发送告警、统计指标、敏感信息脱敏等，Hook在调用方的goroutine中
+// 触发，不持有Logger的锁，多个goroutine可能并发调用同一个Hook，实现需要并发安全
+type Hook interface {
+	// Levels 需要触发Hook的日志级别
+	Levels() []core.LoggerLevel
+	// Fire 触发Hook，可以直接修改entry的内容，返回errorx.ErrEntrySuppressed时丢弃该条日志，
+	// 返回其他的错误会输出到标准错误，不会返回给调用方
+	Fire(entry *HookEntry) error
+}
+
+// resetter 需要在日志文件切换时重置状态的Hook，比如采样的计数器
+type resetter interface {
+	Reset()
+}
+
+// HookEntry 传递给Hook的日志数据
+type HookEntry struct {
+	// 日志时间
+	Time time.Time
+	// 日志级别
+	Level core.LoggerLevel
+	// 格式化完成的日志内容，包括时间、级别前缀、结构化字段和异常级别的堆栈信息
+	Message string
+	// 调用方传入的日志内容，不包括时间、级别前缀、结构化字段和堆栈信息，用于结构化的输出，
+	// 比如OpenTelemetry日志记录的Body
+	RawMessage string
+	// 调用方的堆栈信息
+	Caller core.CallerEntity
+	// 结构化信息
+	Fields []Field
+}
+
+// AddHook 添加Hook，Hook需要是可比较的类型(通常为指针)，以便RemoveHook移除
+func (l *Log) AddHook(h Hook) {
+	if h == nil {
+		return
+	}
+
+	l.hookLock.Lock()
+	defer l.hookLock.Unlock()
+	l.hooks = append(l.hooks, h)
+}
+
+// RemoveHook 移除Hook
+func (l *Log) RemoveHook(h Hook) {
+	l.hookLock.Lock()
+	defer l.hookLock.Unlock()
+
+	// 在拷贝上删除，正在触发的Hook列表不会被修改
+	l.hooks = slices.DeleteFunc(slices.Clone(l.hooks), func(hook Hook) bool {
+		return hook == h
+	})
+}
+
+// resetHooks 重置所有实现了Reset方法的Hook
+func (l *Log) resetHooks() {
+	l.hookLock.RLock()
+	defer l.hookLock.RUnlock()
+
+	for _, h := range l.hooks {
+		if r, ok := h.(resetter); ok {
+			r.Reset()
+		}
+	}
+}
+
+// fireHooks 在调用方的goroutine中同步触发所有匹配日志级别的Hook，触发时不持有任何锁，
+// Hook返回的错误输出到标准错误，有Hook要求丢弃该条日志时返回false，后续的Hook不再触发
+func (l *Log) fireHooks(entry *HookEntry) bool {
+	// 取出Hook列表后释放读锁，Hook中调用AddHook、RemoveHook或者写日志不会死锁，
+	// RemoveHook不会原地修改列表，取出的列表不受影响
+	l.hookLock.RLock()
+	hooks := l.hooks
+	l.hookLock.RUnlock()
+
+	if len(hooks) == 0 {
+		return true
+	}
+
+	callerDone := false
+	for _, h := range hooks {
+		if !slices.Contains(h.Levels(), entry.Level) {
+			continue
+		}
+
+		if !callerDone {
+			// 只有存在匹配的Hook时才获取堆栈信息，避免额外的开销
+			entry.Caller = l.hookCaller.OrignalEntity()
+			callerDone = true
+		}
+
+		err := h.Fire(entry)
+		if errors.Is(err, errorx.ErrEntrySuppressed) {
+			return false
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "logx: fire hook failed: %s\n", err)
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	levels  []core.LoggerLevel
+	err     error
+	lock    sync.Mutex
+	entries []HookEntry
+}
+
+func (h *recordHook) Levels() []core.LoggerLevel {
+	return h.levels
+}
+
+func (h *recordHook) Fire(entry *HookEntry) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.entries = append(h.entries, *entry)
+	entry.Message = strings.ToUpper(entry.Message)
+	return h.err
+}
+
+func TestLog_Hook(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithLevel(core.DebugLevel))
+	assert.NoError(t, err)
+
+	errHook := &recordHook{levels: []core.LoggerLevel{core.ErrorLevel}, err: errors.New("mock error")}
+	allHook := &recordHook{levels: []core.LoggerLevel{core.DebugLevel, core.InfoLevel, core.ErrorLevel}}
+	lg.AddHook(errHook)
+	lg.AddHook(allHook)
+	lg.AddHook(nil)
+
+	lg.Debug("debug message")
+	lg.Infof("info %s", "message")
+	lg.Warn("warn message")
+	lg.Error("error message")
+
+	assert.Len(t, errHook.entries, 1)
+	assert.Contains(t, errHook.entries[0].Message, "error message")
+	assert.Equal(t, core.ErrorLevel, errHook.entries[0].Level)
+	assert.Equal(t, "hook_test.go", filepath.Base(errHook.entries[0].Caller.File()))
+
+	// 后面的Hook可以看到前面Hook修改后的内容
+	assert.Len(t, allHook.entries, 3)
+	assert.Contains(t, allHook.entries[0].Message, "debug message")
+	assert.Contains(t, allHook.entries[1].Message, "info message")
+	assert.Contains(t, allHook.entries[2].Message, "ERROR MESSAGE")
+	assert.Equal(t, "hook_test.go", filepath.Base(allHook.entries[0].Caller.File()))
+
+	lg.RemoveHook(allHook)
+	lg.Info("after remove")
+	assert.Len(t, allHook.entries, 3)
+}
+
+// reentrantHook 在Fire中写日志并移除自身的Hook
+type reentrantHook struct {
+	lg    Logger
+	fired int
+}
+
+func (h *reentrantHook) Levels() []core.LoggerLevel {
+	return []core.LoggerLevel{core.ErrorLevel}
+}
+
+func (h *reentrantHook) Fire(_ *HookEntry) error {
+	h.fired++
+	h.lg.RemoveHook(h)
+	h.lg.Warn("alert sent")
+	return nil
+}
+
+func TestLog_HookReentrant(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	defer lg.Close()
+
+	// Hook触发时不持有锁，在Hook中写日志、移除Hook不会死锁
+	rh := &reentrantHook{lg: lg}
+	allHook := &recordHook{levels: core.AllLevels()}
+	lg.AddHook(rh)
+	lg.AddHook(allHook)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		lg.Error("error message")
+		lg.Error("error message")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook deadlocked")
+	}
+	assert.Equal(t, 1, rh.fired)
+	assert.Len(t, allHook.entries, 3)
+	assert.Contains(t, allHook.entries[0].Message, "alert sent")
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hotreload 监听配置文件的变更，在运行时动态修改日志级别，独立的模块避免core用户引入fsnotify依赖
+package hotreload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// levelConfig 配置文件中只关心日志级别
+type levelConfig struct {
+	Level core.LoggerLevel `yaml:"level" json:"level"`
+}
+
+// Reloader 后台监听配置文件，配置文件写入后重新解析日志级别并调用Logger.SetLevel，
+// 配置文件不合法时输出警告日志并保留当前的日志级别
+type Reloader struct {
+	// 需要修改日志级别的日志实例
+	lg logx.Logger
+	// 配置文件的路径
+	path string
+	// 文件监听器
+	watcher *fsnotify.Watcher
+	// 监听协程退出的信号
+	done chan struct{}
+	// 单例
+	once sync.Once
+}
+
+// NewReloader 创建热加载器并启动后台的监听协程，支持YAML和JSON(.json后缀)格式的配置文件，
+// 监听的是配置文件所在的目录，兼容编辑器先删除再创建的保存方式
+func NewReloader(lg logx.Logger, configPath string) (*Reloader, error) {
+	path, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err = watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	r := &Reloader{
+		lg:      lg,
+		path:    path,
+		watcher: watcher,
+		done:    make(chan struct{}),
+	}
+	go r.watch()
+
+	return r, nil
+}
+
+func (r *Reloader) watch() {
+	defer close(r.done)
+
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != r.path || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				r.lg.Warnf("hot reload config %s failed, keep level %s: %s", r.path, r.lg.GetLevel(), err)
+			}
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.lg.Warnf("hot reload watcher error: %s", err)
+		}
+	}
+}
+
+// reload 重新解析配置文件中的日志级别并修改
+func (r *Reloader) reload() error {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	var cfg levelConfig
+	if strings.EqualFold(filepath.Ext(r.path), ".json") {
+		err = json.Unmarshal(data, &cfg)
+	} else {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !cfg.Level.Valid() {
+		return fmt.Errorf("%w: %d", errorx.ErrLevelInvalid, cfg.Level)
+	}
+
+	r.lg.SetLevel(cfg.Level)
+	return nil
+}
+
+// Close 停止监听，等待后台监听协程退出
+func (r *Reloader) Close() error {
+	var err error
+	r.once.Do(func() {
+		err = r.watcher.Close()
+		<-r.done
+	})
+
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hotreload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		valid    string
+		invalid  string
+	}{
+		{
+			name:     "YAML配置文件",
+			filename: "config.yaml",
+			valid:    "level: error\n",
+			invalid:  "level: verbose\n",
+		},
+		{
+			name:     "JSON配置文件",
+			filename: "config.json",
+			valid:    `{"level":"ERROR"}`,
+			invalid:  `{"level":`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, tc.filename)
+			assert.NoError(t, os.WriteFile(path, []byte(tc.valid), 0o644))
+
+			lg, err := logx.NewLog(dir)
+			assert.NoError(t, err)
+			r, err := NewReloader(lg, path)
+			assert.NoError(t, err)
+			defer r.Close()
+
+			assert.NoError(t, os.WriteFile(path, []byte(tc.valid), 0o644))
+			assert.Eventually(t, func() bool {
+				return lg.GetLevel() == core.ErrorLevel
+			}, time.Second*3, time.Millisecond*10)
+
+			// 不合法的配置保留当前的日志级别
+			assert.NoError(t, os.WriteFile(path, []byte(tc.invalid), 0o644))
+			time.Sleep(time.Millisecond * 100)
+			assert.Equal(t, core.ErrorLevel, lg.GetLevel())
+
+			assert.NoError(t, r.Close())
+			assert.NoError(t, r.Close())
+		})
+	}
+}
@@ -15,28 +15,82 @@
 package logx
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
 )
 
 type Logger interface {
+	Trace(v ...any)
 	Debug(v ...any)
 	Info(v ...any)
+	Notice(v ...any)
 	Warn(v ...any)
 	Error(v ...any)
 	Panic(v ...any)
 	Fatal(v ...any)
+	Tracef(format string, v ...any)
 	Debugf(format string, v ...any)
 	Infof(format string, v ...any)
+	Noticef(format string, v ...any)
 	Warnf(format string, v ...any)
 	Errorf(format string, v ...any)
 	Panicf(format string, v ...any)
 	Fatalf(format string, v ...any)
+	// Tracew 等带w后缀的方法使用交替出现的key、value记录结构化字段，key不是字符串时
+	// 使用fmt.Sprint转换，最后一个落单的value使用EXTRA作为key
+	Tracew(msg string, keysAndValues ...any)
+	Debugw(msg string, keysAndValues ...any)
+	Infow(msg string, keysAndValues ...any)
+	Noticew(msg string, keysAndValues ...any)
+	Warnw(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+	Panicw(msg string, keysAndValues ...any)
+	Fatalw(msg string, keysAndValues ...any)
+	// SetLevel 并发安全的修改日志级别，不合法的日志级别会被忽略
+	SetLevel(level core.LoggerLevel)
+	// GetLevel 获取当前的日志级别
+	GetLevel() core.LoggerLevel
+	// AddHook 添加Hook，在日志写入前同步触发
+	AddHook(h Hook)
+	// RemoveHook 移除Hook
+	RemoveHook(h Hook)
+	// Flush 把缓冲中待写入的日志全部写出，之后日志实例仍然可以正常使用
+	Flush() error
+	// SetWriter 并发安全的添加写入器，key已经存在时替换，关闭之后返回errorx.ErrBufferClose
+	SetWriter(key string, w core.Writer) error
+	// RemoveWriter 并发安全的移除写入器，被移除的写入器不会被关闭
+	RemoveWriter(key string)
+	// Writers 返回当前生效的写入器的key
+	Writers() []string
+	// Close 关闭所有的写入器，关闭之后的日志只会输出到标准输出
+	Close() error
+	// WithError 返回每条日志都携带error字段的子Logger，err为nil时返回自身
+	WithError(err error) Logger
+	// Discard 返回丢弃所有日志的Logger，不会关闭或者影响原有的Logger
+	Discard() Logger
 }
 
+const (
+	// hookCallerSkip Hook获取调用方堆栈信息需要跳过的层级
+	hookCallerSkip = 6
+	// errStackSkip 异常级别获取多级堆栈信息需要跳过的层级，从调用方开始追踪
+	errStackSkip = 4
+)
+
+// levelFileKeyPrefix WithLevelFile注册的写入器的key前缀，后面是文件的路径
+const levelFileKeyPrefix = "level-file:"
+
 const (
 	DefaultErrCoreSkip = 3
 	DefaultLogSize     = 100 * 1024 * 1024
@@ -45,227 +99,699 @@ const (
 	DefaultFilename    = "server.log"
 )
 
+// RotatableLogger 支持主动轮转的Logger，独立于Logger接口，避免所有的实现都需要支持轮转
+type RotatableLogger interface {
+	Logger
+	// RotateNow 立即轮转所有支持轮转的写入器
+	RotateNow() error
+}
+
+// CallDepthLogger 支持在运行时调整调用方层级的Logger，独立于Logger接口，用于logr等适配器跳过适配层
+type CallDepthLogger interface {
+	Logger
+	// WithCallDepth 返回在当前基础上额外跳过depth层调用方的子实例
+	WithCallDepth(depth int) Logger
+}
+
+// TimedLogger 支持指定日志时间的Logger，独立于Logger接口，用于slog等适配器保留原始记录的时间
+type TimedLogger interface {
+	Logger
+	// Logw 使用指定的时间写入level级别的结构化日志，t为零值时使用当前时间
+	Logw(t time.Time, level core.LoggerLevel, msg string, keysAndValues ...any)
+}
+
 type WriteMode int
 
 const (
 	NormalMode WriteMode = iota
 	FormatMode
+	// FieldMode format为日志内容，v为交替出现的key、value
+	FieldMode
 )
 
+// logState 父子日志实例共享的运行时状态，子实例修改日志级别、Hook或者关闭时父实例同样生效
+type logState struct {
+	// 当前的日志级别，支持运行时动态修改
+	level atomic.Uint32
+	// 注册的Hook
+	hooks []Hook
+	// Hook的并发保护
+	hookLock sync.RWMutex
+	// 是否已经关闭
+	closed atomic.Bool
+}
+
 type Log struct {
+	*logState
 	// 配置信息
 	cfg *Config
 	// 并发保护
 	mu *sync.Mutex
 	// 日志加颜色输出
 	cp core.ColorPlugin
+	// Hook获取调用方的堆栈信息
+	hookCaller *core.CallEntityWrap
+	// 异常级别下获取多级堆栈信息
+	stack *core.CallEntityWrap
+	// 获取调用方时额外跳过的层级，包括配置的callDepth和WithCallDepth追加的层级
+	callDepth int
+	// 日志时间的时区，创建时按照配置的location加载
+	loc *time.Location
+	// 日志文件filePath/filename的写入器，不在Writers中，不能被移除
+	file *core.FileWriter
+	// 运行时可以动态添加和移除的写入器
+	writers *core.MultiWriter
+	// 子实例携带的字段，每条日志都会输出
+	fields []Field
 }
 
 func NewLog(filePath string, opts ...Options) (Logger, error) {
 	if filePath == "" {
-		return nil, fmt.Errorf("file path can't be empty")
+		return nil, &errorx.ConfigError{Field: "file path", Reason: "can't be empty"}
 	}
 
-	cfg := &Config{
-		filePath:       filePath,
-		filename:       DefaultFilename,
-		level:          core.InfoLevel,
-		location:       DefaultLocation,
-		enableLine:     true,
-		callSkip:       DefaultErrCoreSkip,
-		threshold:      DefaultLogSize,
-		period:         DefaultPeriod,
-		enableCompress: false,
+	return NewLogWithConfig(NewConfig(filePath, opts...))
+}
+
+// NewLogWithConfig 使用已经构建好的配置创建日志实例，比如从配置文件中加载的配置
+func NewLogWithConfig(cfg *Config) (Logger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	for _, opt := range opts {
-		opt(cfg)
+	file, err := core.OpenFileWriter(filepath.Join(cfg.filePath, cfg.filename))
+	if err != nil {
+		return nil, err
 	}
 
-	l := &Log{
-		cfg: cfg,
-		mu:  new(sync.Mutex),
-		cp:  core.NewANSIColorPlugin(),
+	l := newLog(cfg, new(sync.Mutex), file, core.NewMultiWriter(), nil)
+	if err = l.openLevelFiles(); err != nil {
+		_ = l.Close()
+		return nil, err
 	}
 
 	return l, nil
 }
 
-func (l *Log) prefix(enabled bool, level core.LoggerLevel, v ...any) string {
-	var builder strings.Builder
-	builder.WriteString(l.cp.Format(enabled, level))
-	builder.WriteString(fmt.Sprint(v...))
-	return builder.String()
+// openLevelFiles 打开WithLevelFile配置的文件，使用FilterWriter只写入[level, FatalLevel]之间的日志
+func (l *Log) openLevelFiles() error {
+	var opts []core.FileWriterOptions
+	if l.cfg.formatter != nil {
+		opts = append(opts, core.WithFileFormatter(l.cfg.formatter))
+	}
+
+	for _, lf := range l.cfg.levelFiles {
+		path := l.cfg.levelFilePath(lf)
+		w, err := core.OpenFileWriter(path, opts...)
+		if err != nil {
+			return err
+		}
+		if err = l.SetWriter(levelFileKeyPrefix+path, core.NewFilterWriter(w, lf.level, core.FatalLevel)); err != nil {
+			return errors.Join(err, w.Close())
+		}
+	}
+
+	return nil
 }
 
-func (l *Log) prefixf(enabled bool, level core.LoggerLevel, format string, v ...any) string {
-	var builder strings.Builder
+// newLog 使用已经校验过的配置创建日志实例，Clone时共享并发保护和日志文件，并拷贝写入器和Hook
+func newLog(cfg *Config, mu *sync.Mutex, file *core.FileWriter, writers *core.MultiWriter, hooks []Hook) *Log {
+	l := &Log{
+		logState: &logState{hooks: hooks},
+		cfg:      cfg,
+		mu:       mu,
+		cp:       newColorPlugin(cfg),
+		file:     file,
+		writers:  writers,
+	}
+	l.level.Store(uint32(cfg.level))
+	l.setCallDepth(cfg.callDepth)
+	// 配置已经校验过，加载失败时使用本地时区
+	if l.loc, _ = time.LoadLocation(cfg.location); l.loc == nil {
+		l.loc = time.Local
+	}
+
+	return l
+}
+
+// setCallDepth 设置获取调用方时额外跳过的层级，重新创建获取调用方和堆栈信息的实例
+func (l *Log) setCallDepth(depth int) {
+	l.callDepth = depth
+	// 调用链：caller -> OrignalEntity -> fireHooks/entity -> output/message -> normalExecf/abnormalExecf -> Info等 -> 调用方
+	l.hookCaller = core.NewCallEntityWrap(core.WithSkip(int32(hookCallerSkip + depth)))
+	// 调用链：callers -> Fullnames/OrignalEntities -> abnormalExecf -> Error等 -> 调用方
+	l.stack = core.NewCallEntityWrap(core.WithSkip(int32(errStackSkip+depth)),
+		core.WithMaxDepth(l.cfg.callSkip), core.WithPC())
+}
+
+// WithCallDepth 返回在当前基础上额外跳过depth层调用方的子实例，子实例与父实例共享写入器、Hook和
+// 日志级别，用于logr等适配器在运行时跳过适配层，depth不大于0时返回当前实例
+func (l *Log) WithCallDepth(depth int) Logger {
+	if depth <= 0 {
+		return l
+	}
+
+	child := l.with()
+	child.setCallDepth(l.callDepth + depth)
+	return child
+}
+
+// newColorPlugin 只有开启颜色输出时才使用ANSI颜色插件，默认不输出颜色的转义序列，
+// 避免日志文件和CI日志中出现颜色代码
+func newColorPlugin(cfg *Config) core.ColorPlugin {
+	if !cfg.enableColor {
+		return core.NewNoColorPlugin()
+	}
+
+	return core.NewANSIColorPlugin(core.WithLevelColors(cfg.colorMap))
+}
+
+// WithError 返回携带error字段的子日志实例，子实例与父实例共享写入器、Hook和日志级别。
+// err实现了Unwrap() []error时，每个错误按照顺序输出为error.0、error.1等字段
+func (l *Log) WithError(err error) Logger {
+	if err == nil {
+		return l
+	}
+
+	return l.with(errorFields(err)...)
+}
+
+// Discard 返回不与当前实例共享任何状态的NopLogger，可以在当前实例关闭之后继续使用
+func (l *Log) Discard() Logger {
+	return NewNopLogger()
+}
+
+// Clone 浅拷贝配置并应用opts，返回独立的日志实例，日志级别、Hook和写入器列表与当前实例互不影响，
+// 写入器本身是共享的，关闭任意一个实例都会关闭共享的写入器。opts不合法时忽略opts并输出到标准错误
+func (l *Log) Clone(opts ...Options) Logger {
+	cfg := *l.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "logx: clone logger with invalid options: %s\n", err)
+		cfg = *l.cfg
+	}
+
+	l.hookLock.RLock()
+	hooks := slices.Clone(l.hooks)
+	l.hookLock.RUnlock()
+
+	writers := core.NewMultiWriter()
+	for _, w := range l.writers.Writers() {
+		writers.AddWriter(w.Key, w.Writer, w.Priority)
+	}
+
+	clone := newLog(&cfg, l.mu, l.file, writers, hooks)
+	// 使用新的底层数组，父实例的底层数组有剩余容量时，双方append不会互相覆盖
+	clone.fields = append([]Field(nil), l.fields...)
+
+	return clone
+}
+
+// with 浅拷贝日志实例并追加字段，字段使用新的底层数组，避免父子实例append时互相影响
+func (l *Log) with(fields ...Field) *Log {
+	child := *l
+	child.fields = slices.Concat(l.fields, fields)
+
+	return &child
+}
+
+func (l *Log) SetLevel(level core.LoggerLevel) {
+	if !level.Valid() {
+		return
+	}
+
+	l.level.Store(uint32(level))
+}
+
+func (l *Log) GetLevel() core.LoggerLevel {
+	return core.LoggerLevel(l.level.Load())
+}
+
+// allow 当前的日志级别是否允许输出level级别的日志
+func (l *Log) allow(level core.LoggerLevel) bool {
+	return l.GetLevel().Enabled(level)
+}
+
+// now 按照配置获取当前的日志时间
+func (l *Log) now() time.Time {
+	return l.normalize(time.Now())
+}
+
+// normalize 按照配置转换日志时间的时区，并去掉单调时钟读数，开启UTC时优先使用UTC时间
+func (l *Log) normalize(t time.Time) time.Time {
+	if l.cfg.stripMonotonic {
+		t = t.Round(0)
+	}
+	if l.cfg.enableUTC {
+		return t.UTC()
+	}
+
+	return t.In(l.loc)
+}
+
+// sprint 只有一个字符串参数时直接返回，避免fmt.Sprint的内存分配
+func sprint(v ...any) string {
+	if len(v) == 1 {
+		if s, ok := v[0].(string); ok {
+			return s
+		}
+	}
+
+	return fmt.Sprint(v...)
+}
+
+// sprintf 没有格式化参数且没有格式化动词时直接返回format，避免fmt.Sprintf的内存分配，
+// 包含%时仍需要fmt.Sprintf处理"%%"等转义
+func sprintf(format string, v ...any) string {
+	if len(v) == 0 && strings.IndexByte(format, '%') < 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, v...)
+}
+
+// maxPooledBuilderSize 放回对象池的缓冲区容量上限，避免超大日志长期占用内存
+const maxPooledBuilderSize = 64 << 10
+
+// builderPool 日志前缀拼接缓冲区对象池，减少高并发场景下的内存分配和GC压力，
+// strings.Builder在Reset时会丢弃底层数组，无法复用，所以使用bytes.Buffer
+var builderPool = sync.Pool{
+	New: func() interface{} {
+		return &bytes.Buffer{}
+	},
+}
+
+// buildMsg 使用对象池中的缓冲区拼接时间、级别前缀和日志内容，
+// String()会拷贝一份数据，缓冲区放回对象池后不再被引用
+func (l *Log) buildMsg(t time.Time, enabled bool, level core.LoggerLevel, content string) string {
+	builder, _ := builderPool.Get().(*bytes.Buffer)
+	builder.Reset()
+	builder.WriteString(t.Format(l.cfg.timeLayout))
+	builder.WriteByte(' ')
 	builder.WriteString(l.cp.Format(enabled, level))
-	if level.Prohibit(core.InfoLevel) {
-		// TODO 处理这个分支
+	builder.WriteString(content)
+	msg := builder.String()
+	if builder.Cap() <= maxPooledBuilderSize {
+		builderPool.Put(builder)
+	}
+
+	return msg
+}
+
+// Trace 最详细的追踪日志，不会携带堆栈信息
+func (l *Log) Trace(v ...any) {
+	if !l.allow(core.TraceLevel) {
+		return
 	}
-	builder.WriteString(fmt.Sprintf(format, v...))
-	return builder.String()
+
+	l.normalExecf(l.now(), NormalMode, core.TraceLevel, "", v)
 }
 
 func (l *Log) Debug(v ...any) {
-	if l.cfg.level.Prohibit(core.DebugLevel) {
+	if !l.allow(core.DebugLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.normalExecf(NormalMode, core.DebugLevel, "", v...)
+	l.normalExecf(l.now(), NormalMode, core.DebugLevel, "", v)
 }
 
 func (l *Log) Info(v ...any) {
-	if l.cfg.level.Prohibit(core.InfoLevel) {
+	if !l.allow(core.InfoLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.normalExecf(NormalMode, core.InfoLevel, "", v...)
+	l.normalExecf(l.now(), NormalMode, core.InfoLevel, "", v)
+}
+
+func (l *Log) Notice(v ...any) {
+	if !l.allow(core.NoticeLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), NormalMode, core.NoticeLevel, "", v)
 }
 
 func (l *Log) Warn(v ...any) {
-	if l.cfg.level.Prohibit(core.WarnLevel) {
+	if !l.allow(core.WarnLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.normalExecf(NormalMode, core.WarnLevel, "", v...)
+	l.normalExecf(l.now(), NormalMode, core.WarnLevel, "", v)
 }
 
 func (l *Log) Error(v ...any) {
-	if l.cfg.level.Prohibit(core.ErrorLevel) {
+	if !l.allow(core.ErrorLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.abnormalExecf(NormalMode, core.ErrorLevel, "", v...)
+	l.abnormalExecf(l.now(), NormalMode, core.ErrorLevel, "", v)
 }
 
 func (l *Log) Panic(v ...any) {
-	if l.cfg.level.Prohibit(core.PanicLevel) {
+	if !l.allow(core.PanicLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.abnormalExecf(NormalMode, core.PanicLevel, "", v...)
+	l.abnormalExecf(l.now(), NormalMode, core.PanicLevel, "", v)
 }
 
 func (l *Log) Fatal(v ...any) {
-	if l.cfg.level.Prohibit(core.FatalLevel) {
+	if !l.allow(core.FatalLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.abnormalExecf(NormalMode, core.FatalLevel, "", v...)
+	l.abnormalExecf(l.now(), NormalMode, core.FatalLevel, "", v)
+}
+
+func (l *Log) Tracef(format string, v ...any) {
+	if !l.allow(core.TraceLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FormatMode, core.TraceLevel, format, v)
 }
 
 func (l *Log) Debugf(format string, v ...any) {
-	if l.cfg.level.Prohibit(core.DebugLevel) {
+	if !l.allow(core.DebugLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.normalExecf(FormatMode, core.DebugLevel, format, v...)
+	l.normalExecf(l.now(), FormatMode, core.DebugLevel, format, v)
 }
 
 func (l *Log) Infof(format string, v ...any) {
-	if l.cfg.level.Prohibit(core.InfoLevel) {
+	if !l.allow(core.InfoLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.normalExecf(FormatMode, core.InfoLevel, format, v...)
+	l.normalExecf(l.now(), FormatMode, core.InfoLevel, format, v)
+}
+
+func (l *Log) Noticef(format string, v ...any) {
+	if !l.allow(core.NoticeLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FormatMode, core.NoticeLevel, format, v)
 }
 
 func (l *Log) Warnf(format string, v ...any) {
-	if l.cfg.level.Prohibit(core.WarnLevel) {
+	if !l.allow(core.WarnLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.normalExecf(FormatMode, core.WarnLevel, format, v...)
+	l.normalExecf(l.now(), FormatMode, core.WarnLevel, format, v)
 }
 
 func (l *Log) Errorf(format string, v ...any) {
-	if l.cfg.level.Prohibit(core.ErrorLevel) {
+	if !l.allow(core.ErrorLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.abnormalExecf(FormatMode, core.ErrorLevel, format, v...)
+	l.abnormalExecf(l.now(), FormatMode, core.ErrorLevel, format, v)
 }
 
 func (l *Log) Panicf(format string, v ...any) {
-	if l.cfg.level.Prohibit(core.PanicLevel) {
+	if !l.allow(core.PanicLevel) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
-	l.abnormalExecf(FormatMode, core.PanicLevel, format, v...)
+	l.abnormalExecf(l.now(), FormatMode, core.PanicLevel, format, v)
 }
 
 func (l *Log) Fatalf(format string, v ...any) {
-	if l.cfg.level.Prohibit(core.FatalLevel) {
+	if !l.allow(core.FatalLevel) {
+		return
+	}
+
+	l.abnormalExecf(l.now(), FormatMode, core.FatalLevel, format, v)
+}
+
+func (l *Log) Tracew(msg string, keysAndValues ...any) {
+	if !l.allow(core.TraceLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FieldMode, core.TraceLevel, msg, keysAndValues)
+}
+
+func (l *Log) Debugw(msg string, keysAndValues ...any) {
+	if !l.allow(core.DebugLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FieldMode, core.DebugLevel, msg, keysAndValues)
+}
+
+func (l *Log) Infow(msg string, keysAndValues ...any) {
+	if !l.allow(core.InfoLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FieldMode, core.InfoLevel, msg, keysAndValues)
+}
+
+func (l *Log) Noticew(msg string, keysAndValues ...any) {
+	if !l.allow(core.NoticeLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FieldMode, core.NoticeLevel, msg, keysAndValues)
+}
+
+func (l *Log) Warnw(msg string, keysAndValues ...any) {
+	if !l.allow(core.WarnLevel) {
+		return
+	}
+
+	l.normalExecf(l.now(), FieldMode, core.WarnLevel, msg, keysAndValues)
+}
+
+func (l *Log) Errorw(msg string, keysAndValues ...any) {
+	if !l.allow(core.ErrorLevel) {
+		return
+	}
+
+	l.abnormalExecf(l.now(), FieldMode, core.ErrorLevel, msg, keysAndValues)
+}
+
+func (l *Log) Panicw(msg string, keysAndValues ...any) {
+	if !l.allow(core.PanicLevel) {
+		return
+	}
+
+	l.abnormalExecf(l.now(), FieldMode, core.PanicLevel, msg, keysAndValues)
+}
+
+func (l *Log) Fatalw(msg string, keysAndValues ...any) {
+	if !l.allow(core.FatalLevel) {
+		return
+	}
+
+	l.abnormalExecf(l.now(), FieldMode, core.FatalLevel, msg, keysAndValues)
+}
+
+// Logw 使用指定的时间写入结构化日志，ErrorLevel及以上的级别携带堆栈信息，与Errorw等方法一致
+func (l *Log) Logw(t time.Time, level core.LoggerLevel, msg string, keysAndValues ...any) {
+	if !level.Valid() || !l.allow(level) {
 		return
 	}
 
+	if t.IsZero() {
+		t = l.now()
+	} else {
+		t = l.normalize(t)
+	}
+
+	if level >= core.ErrorLevel {
+		l.abnormalExecf(t, FieldMode, level, msg, keysAndValues)
+		return
+	}
+	l.normalExecf(t, FieldMode, level, msg, keysAndValues)
+}
+
+// Flush 等待正在执行的写入完成，并刷新所有的写入器
+func (l *Log) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return errors.Join(l.file.Flush(), l.writers.Flush())
+}
+
+// SetWriter 在l.mu内检查是否已经关闭并添加写入器，Close先标记关闭再获取l.mu关闭所有的写入器，
+// 所以添加的写入器要么被Close关闭，要么返回errorx.ErrBufferClose，不会遗漏
+func (l *Log) SetWriter(key string, w core.Writer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed.Load() {
+		return errorx.ErrBufferClose
+	}
+
+	l.writers.AddWriter(key, w, 0)
+	return nil
+}
+
+func (l *Log) RemoveWriter(key string) {
+	l.writers.RemoveWriter(key)
+}
+
+func (l *Log) Writers() []string {
+	writers := l.writers.Writers()
+	keys := make([]string, 0, len(writers))
+	for _, w := range writers {
+		keys = append(keys, w.Key)
+	}
+
+	return keys
+}
+
+// Close 等待正在执行的写入完成后关闭所有的写入器，重复调用直接返回
+func (l *Log) Close() error {
+	if !l.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	l.abnormalExecf(FormatMode, core.FatalLevel, format, v...)
+
+	return errors.Join(l.file.Close(), l.writers.Close())
 }
 
-// normalExecf 正常级别下真正执行写入的方法
-func (l *Log) normalExecf(mode WriteMode, level core.LoggerLevel, format string, v ...any) {
-	var msg string
-	switch mode {
-	case NormalMode:
-		msg = l.prefix(false, level, v...)
-	case FormatMode:
-		msg = l.prefixf(false, level, format, v...)
+// RotateNow 等待正在执行的写入完成后，轮转所有实现了core.Rotator接口的写入器，轮转成功后
+// 重置所有实现了Reset方法的Hook(比如SamplingHook)，轮转失败时返回包装了errorx.ErrRotateFailed的错误，
+// 关闭之后返回errorx.ErrBufferClose
+func (l *Log) RotateNow() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 持有锁之后再检查，避免并发的Close关闭文件之后重新打开文件
+	if l.closed.Load() {
+		return errorx.ErrBufferClose
 	}
 
-	fmt.Println(msg)
+	if err := errors.Join(l.file.Rotate(), l.writers.Rotate()); err != nil {
+		return fmt.Errorf("%w: %w", errorx.ErrRotateFailed, err)
+	}
+	l.resetHooks()
+
+	return nil
 }
 
-// abnormalExecf 异常级别下真正执行写入的方法
-func (l *Log) abnormalExecf(mode WriteMode, level core.LoggerLevel, format string, v ...any) {
-	var msg string
+// entity 从对象池中获取并构造结构化的日志数据，用于配置了格式化器的场景，frames为异常级别的
+// 堆栈信息，为空时使用调用方的堆栈信息，格式化完成后需要调用core.PutEntity放回
+func (l *Log) entity(t time.Time, level core.LoggerLevel, raw string, fields []Field,
+	frames []core.CallerEntity) *core.Entity {
+	e := core.GetEntity()
+	e.Timestamp = t.UnixNano()
+	e.Level = level
+	e.Message = raw
+	putFields(e.Fields, fields)
+	if len(frames) > 0 {
+		e.CE = append(e.CE, frames...)
+	} else {
+		e.CE = append(e.CE, l.hookCaller.OrignalEntity())
+	}
+
+	return e
+}
+
+// message 按照写入模式构造带前缀的日志内容，同时返回不带前缀和字段的日志内容，以及FieldMode下转换后的字段
+func (l *Log) message(t time.Time, mode WriteMode, level core.LoggerLevel, format string, v []any,
+	frames []core.CallerEntity) (msg, raw string, fields []Field) {
+	// 拷贝子实例的字段，Hook修改字段时不会影响子实例
+	fields = slices.Clone(l.fields)
 	switch mode {
 	case NormalMode:
-		msg = l.prefix(true, level, v...)
+		raw = sprint(v...)
 	case FormatMode:
-		msg = l.prefixf(false, level, format, v...)
+		raw = sprintf(format, v...)
+	default:
+		raw = format
+		fields = append(fields, sweetenFields(v)...)
+	}
+	if l.cfg.formatter != nil {
+		e := l.entity(t, level, raw, fields, frames)
+		msg = string(l.cfg.formatter.Format(*e))
+		core.PutEntity(e)
+		return msg, raw, fields
 	}
-	fmt.Println(msg)
+
+	return l.buildMsg(t, l.cfg.enableColor, level, raw) + encodeFields(fields), raw, fields
 }
 
-// abnormalStack 用于打印异常情况下的多行堆栈信息，特殊处理，Debug、Info级别不需要
-// 返回写入的数据大小
-//
-//nolint:unused  // 后边要使用
-func (l *Log) abnormalStack() int {
+// normalExecf 正常级别下真正执行写入的方法，t为日志时间，日志前缀、Hook和写入器使用同一个时间
+func (l *Log) normalExecf(t time.Time, mode WriteMode, level core.LoggerLevel, format string, v []any) {
+	msg, raw, fields := l.message(t, mode, level, format, v, nil)
+	l.output(t, level, msg, raw, fields, nil)
+}
+
+// abnormalExecf 异常级别下真正执行写入的方法
+func (l *Log) abnormalExecf(t time.Time, mode WriteMode, level core.LoggerLevel, format string, v []any) {
+	// 堆栈信息作为Entity.CE交给格式化器和写入器，比如GELF的full_message
+	frames := l.stack.OrignalEntities()
+	if len(frames) > l.cfg.callSkip {
+		frames = frames[:l.cfg.callSkip]
+	}
+	msg, raw, fields := l.message(t, mode, level, format, v, frames)
+	if l.cfg.formatter != nil {
+		l.output(t, level, msg, raw, fields, frames)
+		return
+	}
+
+	// 异常级别下在日志内容后追加多行的堆栈信息，Debug、Info级别不需要
+	names := l.stack.Fullnames()
+	if len(names) > l.cfg.callSkip {
+		names = names[:l.cfg.callSkip]
+	}
 	var builder strings.Builder
-	//for _, s := range MultiLevel(l.cfg.callSkip) {
-	//	str := "\t" + s + "\n"
-	//	builder.WriteString(str)
-	//}
-
-	res := builder.String()
-	//_, _ = l.rs.logout.WriteString(res)
-	fmt.Println(res)
-	return len(res)
+	builder.WriteString(msg)
+	for _, name := range names {
+		builder.WriteString("\n\t")
+		builder.WriteString(name)
+	}
+
+	l.output(t, level, builder.String(), raw, fields, frames)
+}
+
+// output 触发Hook后输出格式化完成的日志，Hook可以修改日志内容，frames为异常级别的堆栈信息
+func (l *Log) output(t time.Time, level core.LoggerLevel, msg, raw string, fields []Field,
+	frames []core.CallerEntity) {
+	entry := &HookEntry{
+		Time:       t,
+		Level:      level,
+		Message:    msg,
+		RawMessage: raw,
+		Fields:     fields,
+	}
+	if !l.fireHooks(entry) {
+		return
+	}
+
+	// Hook在锁外触发，只有写入需要持有锁，保证写入的顺序以及Flush、Close等待正在执行的写入
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed.Load() {
+		fmt.Println(entry.Message)
+		return
+	}
+
+	if _, err := l.file.Write([]byte(entry.Message + "\n")); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "logx: write log file failed: %s\n", err)
+	}
+
+	// 运行时添加的写入器通过WriteEntity写入，FilterWriter等装饰器可以按照日志级别和字段处理，
+	// Message为原始消息，字段只保留在Fields中，避免重复输出以及MaskField无法脱敏消息中的字段
+	e := core.GetEntity()
+	e.Timestamp = entry.Time.UnixNano()
+	e.Level = entry.Level
+	e.Message = entry.RawMessage
+	putFields(e.Fields, entry.Fields)
+	e.CE = append(e.CE, frames...)
+	if err := l.writers.WriteEntity(*e); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "logx: write log failed: %s\n", err)
+	}
+	core.PutEntity(e)
 }
@@ -13,3 +13,793 @@
 // limitations under the License.
 
 package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_SetLevel(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithLevel(core.WarnLevel))
+	assert.NoError(t, err)
+	l, _ := lg.(*Log)
+
+	assert.Equal(t, core.WarnLevel, lg.GetLevel())
+	assert.False(t, l.allow(core.InfoLevel))
+	assert.True(t, l.allow(core.WarnLevel))
+	assert.True(t, l.allow(core.ErrorLevel))
+
+	lg.SetLevel(core.DebugLevel)
+	assert.Equal(t, core.DebugLevel, lg.GetLevel())
+	assert.True(t, l.allow(core.DebugLevel))
+
+	// 不合法的日志级别被忽略
+	lg.SetLevel(core.LoggerLevel(100))
+	assert.Equal(t, core.DebugLevel, lg.GetLevel())
+}
+
+func TestLog_Trace(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithCallSkip(2))
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.TraceLevel}}
+	lg.AddHook(rh)
+
+	// 默认不输出Trace日志
+	lg.Trace("trace message")
+	assert.Empty(t, rh.entries)
+
+	lg.SetLevel(core.TraceLevel)
+	lg.Trace("trace message")
+	lg.Tracef("trace %s", "message")
+	assert.Len(t, rh.entries, 2)
+	for _, entry := range rh.entries {
+		assert.Contains(t, entry.Message, "[TRACE] ")
+		// 不携带堆栈信息
+		assert.NotContains(t, entry.Message, "\n")
+	}
+}
+
+func TestLog_Notice(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithLevel(core.WarnLevel))
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.NoticeLevel}}
+	lg.AddHook(rh)
+
+	// 高于NoticeLevel的日志级别不输出Notice日志
+	lg.Notice("notice message")
+	assert.Empty(t, rh.entries)
+
+	lg.SetLevel(core.InfoLevel)
+	lg.Notice("notice message")
+	lg.Noticef("notice %s", "message")
+	lg.Noticew("notice message", "user", "tom")
+	assert.Len(t, rh.entries, 3)
+	for _, entry := range rh.entries {
+		assert.Equal(t, core.NoticeLevel, entry.Level)
+		assert.Contains(t, entry.Message, "[NOTICE] ")
+	}
+}
+
+func TestLog_Fields(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel, core.ErrorLevel}}
+	lg.AddHook(rh)
+
+	lg.Debugw("debug message", "key", "value")
+	lg.Infow("info message", "user", "tom", "age", 18, "dangling")
+	lg.Errorw("error message", "err", errors.New("mock error"))
+	assert.Len(t, rh.entries, 2)
+	assert.True(t, strings.HasSuffix(rh.entries[0].Message, "info message user=tom age=18 EXTRA=dangling"))
+	assert.Equal(t, []Field{
+		{Key: "user", Type: StringTypeField, Value: "tom"},
+		{Key: "age", Type: IntTypeField, Value: 18},
+		{Key: extraFieldKey, Type: StringTypeField, Value: "dangling"},
+	}, rh.entries[0].Fields)
+	assert.Contains(t, rh.entries[1].Message, `error message err="mock error"`)
+
+	// 配置了格式化器时，字段作为结构化数据输出
+	lg, err = NewLog(t.TempDir(), WithLogfmtFormat())
+	assert.NoError(t, err)
+	rh = &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+	lg.Infow("info message", "user", "tom")
+	assert.Len(t, rh.entries, 1)
+	assert.Contains(t, rh.entries[0].Message, `msg="info message"`)
+	assert.Contains(t, rh.entries[0].Message, `user=tom`)
+
+	// 重复的key被重命名，不会丢弃
+	lg.Infow("info message", "user", "tom", "user", "jerry")
+	assert.Len(t, rh.entries, 2)
+	assert.Contains(t, rh.entries[1].Message, `user=tom user_dup_1=jerry`)
+}
+
+func TestLog_LogfmtCaller(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithLogfmtFormat())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+
+	_, _, line, _ := runtime.Caller(0)
+	lg.Info("info message")
+	assert.Len(t, rh.entries, 1)
+	assert.Contains(t, rh.entries[0].Message, fmt.Sprintf("log_test.go:%d", line+1))
+	assert.Regexp(t, `caller=\S+/log_test.go:\d+`, rh.entries[0].Message)
+}
+
+func TestLog_WithError(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+
+	assert.Same(t, lg, lg.WithError(nil))
+
+	child := lg.WithError(errors.New("mock error"))
+	child.Info("info message")
+	child.Infof("info %s", "message")
+	child.Infow("info message", "user", "tom")
+	lg.Info("parent message")
+	assert.Len(t, rh.entries, 4)
+	assert.True(t, strings.HasSuffix(rh.entries[0].Message, `info message error="mock error"`))
+	assert.True(t, strings.HasSuffix(rh.entries[1].Message, `info message error="mock error"`))
+	assert.True(t, strings.HasSuffix(rh.entries[2].Message, `info message error="mock error" user=tom`))
+	// 父实例不受影响
+	assert.True(t, strings.HasSuffix(rh.entries[3].Message, "parent message"))
+	assert.Nil(t, rh.entries[3].Fields)
+
+	// 子实例与父实例共享日志级别
+	child.SetLevel(core.WarnLevel)
+	assert.Equal(t, core.WarnLevel, lg.GetLevel())
+
+	// 组合错误按照顺序输出为多个字段
+	lg.SetLevel(core.InfoLevel)
+	lg.WithError(errors.Join(errors.New("e1"), errors.New("e2"))).Info("joined")
+	assert.Len(t, rh.entries, 5)
+	assert.Equal(t, []Field{
+		{Key: "error.0", Type: StringTypeField, Value: "e1"},
+		{Key: "error.1", Type: StringTypeField, Value: "e2"},
+	}, rh.entries[4].Fields)
+
+	// 配置了格式化器时，字段作为结构化数据输出
+	lg, err = NewLog(t.TempDir(), WithLogfmtFormat())
+	assert.NoError(t, err)
+	rh = &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+	lg.WithError(errors.New("mock error")).Info("info message")
+	assert.Len(t, rh.entries, 1)
+	assert.Contains(t, rh.entries[0].Message, `error="mock error"`)
+}
+
+func TestLog_Discard(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+
+	nop := lg.Discard()
+	assert.IsType(t, NopLogger{}, nop)
+	nop.Info("discarded")
+	assert.NoError(t, nop.Close())
+	assert.Empty(t, rh.entries)
+
+	// 原有的实例不受影响
+	lg.Info("info message")
+	assert.Len(t, rh.entries, 1)
+	assert.NoError(t, lg.Close())
+	nop.Info("discarded")
+}
+
+func TestLog_ErrorStack(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithCallSkip(2))
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.ErrorLevel}}
+	lg.AddHook(rh)
+
+	lg.Error("error message")
+	lg.Errorf("error %s", "message")
+	assert.Len(t, rh.entries, 2)
+	for _, entry := range rh.entries {
+		lines := strings.Split(entry.Message, "\n")
+		assert.Len(t, lines, 3)
+		assert.Contains(t, lines[0], "error message")
+		// 第一帧是调用方，而不是logx的内部方法
+		assert.True(t, strings.HasPrefix(lines[1], "\t"))
+		assert.Contains(t, lines[1], "log_test.go")
+		assert.Contains(t, lines[1], "TestLog_ErrorStack")
+	}
+}
+
+// requestLogger 模拟封装logx的中间件，调用链为调用方 -> logError -> Error
+type requestLogger struct {
+	lg Logger
+}
+
+func (r requestLogger) logError(msg string) {
+	r.error(msg)
+}
+
+func (r requestLogger) error(msg string) {
+	r.lg.Error(msg)
+}
+
+func TestLog_WithCallDepth(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithCallSkip(1), WithCallDepth(2))
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.ErrorLevel}}
+	lg.AddHook(rh)
+
+	_, _, line, _ := runtime.Caller(0)
+	requestLogger{lg: lg}.logError("wrapped error")
+	assert.Len(t, rh.entries, 1)
+	assert.Equal(t, "log_test.go", filepath.Base(rh.entries[0].Caller.File()))
+	assert.Equal(t, line+1, rh.entries[0].Caller.Line())
+
+	lines := strings.Split(rh.entries[0].Message, "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[1], fmt.Sprintf("log_test.go line:%d", line+1))
+	assert.Contains(t, lines[1], "TestLog_WithCallDepth")
+}
+
+// infoLogger 模拟只有一层封装的中间件，调用链为调用方 -> info -> Infow
+type infoLogger struct {
+	lg Logger
+}
+
+func (i infoLogger) info(msg string) {
+	i.lg.Infow(msg, "wrapped", true)
+}
+
+func TestLog_WithCallDepth_OneLevel(t *testing.T) {
+	dir := t.TempDir()
+	lg, err := NewLog(dir, WithCallDepth(1), WithLogfmtFormat())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+
+	_, _, line, _ := runtime.Caller(0)
+	infoLogger{lg: lg}.info("wrapped info")
+	// 不跳过封装层时获取到的是封装层的位置
+	plain := lg.(*Log).Clone(WithCallDepth(0))
+	_, _, plainLine, _ := runtime.Caller(0)
+	infoLogger{lg: plain}.info("plain info")
+	// 运行时追加层级的子实例与父实例共享Hook
+	infoLogger{lg: plain.(CallDepthLogger).WithCallDepth(1)}.info("derived info")
+	assert.Equal(t, plain, plain.(CallDepthLogger).WithCallDepth(0))
+	assert.NoError(t, lg.Close())
+
+	assert.Len(t, rh.entries, 3)
+	assert.Equal(t, "log_test.go", filepath.Base(rh.entries[0].Caller.File()))
+	assert.Equal(t, line+1, rh.entries[0].Caller.Line())
+	assert.NotEqual(t, plainLine+1, rh.entries[1].Caller.Line())
+	assert.Equal(t, plainLine+3, rh.entries[2].Caller.Line())
+	// 格式化器输出的调用方同样跳过封装层，recordHook会把日志内容转换为大写
+	data, err := os.ReadFile(filepath.Join(dir, DefaultFilename))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), fmt.Sprintf("LOG_TEST.GO:%d", line+1))
+}
+
+func TestLog_Clone(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	parent := lg.(*Log)
+	// 底层数组有剩余容量，共享底层数组时双方的append会互相覆盖
+	parent.fields = make([]Field, 1, 8)
+	parent.fields[0] = StringField("service", "order")
+
+	clone, _ := parent.Clone(WithLevel(core.WarnLevel)).(*Log)
+	assert.Equal(t, core.WarnLevel, clone.GetLevel())
+	assert.Equal(t, core.InfoLevel, parent.GetLevel())
+	assert.Equal(t, parent.fields, clone.fields)
+
+	// 子实例追加的字段不会出现在父实例中，父实例在克隆之后追加的字段也不会出现在子实例中
+	clone.fields = append(clone.fields, StringField("role", "clone"))
+	parent.fields = append(parent.fields, StringField("role", "parent"))
+	assert.Equal(t, []Field{StringField("service", "order"), StringField("role", "clone")}, clone.fields)
+	assert.Equal(t, []Field{StringField("service", "order"), StringField("role", "parent")}, parent.fields)
+
+	// 不合法的opts被忽略
+	invalid, _ := parent.Clone(WithLevel(core.LoggerLevel(100))).(*Log)
+	assert.Equal(t, core.InfoLevel, invalid.GetLevel())
+
+	parentHook := &recordHook{levels: []core.LoggerLevel{core.WarnLevel}}
+	cloneHook := &recordHook{levels: []core.LoggerLevel{core.WarnLevel}}
+	parent.AddHook(parentHook)
+	clone.AddHook(cloneHook)
+
+	const n = 50
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			parent.Warnw("parent message", "i", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			clone.Warnw("clone message", "i", i)
+		}
+	}()
+	wg.Wait()
+
+	assert.Len(t, parentHook.entries, n)
+	assert.Len(t, cloneHook.entries, n)
+	for i := 0; i < n; i++ {
+		assert.True(t, strings.HasSuffix(parentHook.entries[i].Message,
+			fmt.Sprintf("parent message service=order role=parent i=%d", i)))
+		assert.True(t, strings.HasSuffix(cloneHook.entries[i].Message,
+			fmt.Sprintf("clone message service=order role=clone i=%d", i)))
+	}
+}
+
+func TestLog_Color(t *testing.T) {
+	plain, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	colored, err := NewLog(t.TempDir(), WithColor())
+	assert.NoError(t, err)
+	// 测试环境的标准输出不是终端，强制开启颜色输出
+	colored.(*Log).cp = core.NewANSIColorPlugin(core.WithForceColor())
+	assert.IsType(t, core.NoColorPlugin{}, plain.(*Log).cp)
+
+	for _, lg := range []Logger{plain, colored} {
+		rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel, core.ErrorLevel}}
+		lg.AddHook(rh)
+		lg.Info("info message")
+		lg.Error("error message")
+		lg.Errorf("error %s", "message")
+		assert.Len(t, rh.entries, 3)
+
+		enabled := lg == colored
+		for _, entry := range rh.entries {
+			assert.Equal(t, enabled, strings.Contains(entry.Message, "\x1b["))
+		}
+	}
+}
+
+func TestLog_Flush(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(rh)
+
+	lg.Info("before flush")
+	assert.NoError(t, lg.Flush())
+	// Flush之后日志实例仍然可以正常使用
+	lg.Info("after flush")
+	assert.Len(t, rh.entries, 2)
+}
+
+// rotateBufWriter 支持轮转的内存写入器，轮转时清空已经写入的内容
+type rotateBufWriter struct {
+	bufWriter
+	rotates int
+	err     error
+}
+
+func (w *rotateBufWriter) Rotate() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.rotates++
+	if w.err != nil {
+		return w.err
+	}
+	w.buf.Reset()
+	return nil
+}
+
+func TestLog_RotateNow(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rl, ok := lg.(RotatableLogger)
+	assert.True(t, ok)
+
+	w := &rotateBufWriter{}
+	assert.NoError(t, rl.SetWriter("rotate", w))
+	assert.NoError(t, rl.SetWriter("plain", &bufWriter{}))
+	rl.Info("before rotate")
+	assert.NoError(t, rl.RotateNow())
+	assert.Equal(t, 1, w.rotates)
+	assert.Empty(t, w.String())
+
+	rl.Info("after rotate")
+	assert.Contains(t, w.String(), "after rotate")
+
+	mockErr := errors.New("mock error")
+	assert.NoError(t, rl.SetWriter("failed", &rotateBufWriter{err: mockErr}))
+	err = rl.RotateNow()
+	assert.ErrorIs(t, err, errorx.ErrRotateFailed)
+	assert.ErrorIs(t, err, mockErr)
+
+	assert.NoError(t, rl.Close())
+	assert.ErrorIs(t, rl.RotateNow(), errorx.ErrBufferClose)
+}
+
+// bufWriter 把日志写入内存的写入器
+type bufWriter struct {
+	lock   sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (w *bufWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.buf.Write(p)
+}
+
+func (w *bufWriter) WriteEntity(e core.Entity) error {
+	_, err := w.Write([]byte(e.Message + "\n"))
+	return err
+}
+
+func (w *bufWriter) Flush() error {
+	return nil
+}
+
+func (w *bufWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+func (w *bufWriter) String() string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.buf.String()
+}
+
+func TestLog_SetWriter(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	w1, w2 := &bufWriter{}, &bufWriter{}
+
+	assert.NoError(t, lg.SetWriter("w1", w1))
+	assert.NoError(t, lg.SetWriter("w2", w2))
+	assert.Equal(t, []string{"w1", "w2"}, lg.Writers())
+	lg.Info("first message")
+
+	lg.RemoveWriter("w2")
+	assert.Equal(t, []string{"w1"}, lg.Writers())
+	lg.Info("second message")
+	assert.Equal(t, 2, strings.Count(w1.String(), "\n"))
+	assert.Contains(t, w1.String(), "second message")
+	assert.Contains(t, w2.String(), "first message")
+	assert.NotContains(t, w2.String(), "second message")
+	assert.False(t, w2.closed)
+
+	// 写入器的修改和日志写入可以并发执行
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			lg.Infof("message %d", i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = lg.SetWriter("w2", w2)
+			lg.RemoveWriter("w2")
+		}
+	}()
+	wg.Wait()
+
+	assert.NoError(t, lg.Flush())
+	assert.NoError(t, lg.Close())
+	assert.True(t, w1.closed)
+	assert.ErrorIs(t, lg.SetWriter("w2", w2), errorx.ErrBufferClose)
+	assert.NoError(t, lg.Close())
+}
+
+func TestLog_SetWriterClose(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		lg, err := NewLog(t.TempDir())
+		assert.NoError(t, err)
+
+		// 与Close并发添加的写入器，添加成功的一定会被Close关闭
+		writers := make([]*bufWriter, 50)
+		added := make([]bool, len(writers))
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range writers {
+				writers[i] = &bufWriter{}
+				added[i] = lg.SetWriter(strconv.Itoa(i), writers[i]) == nil
+			}
+		}()
+		assert.NoError(t, lg.Close())
+		wg.Wait()
+
+		for i, w := range writers {
+			if added[i] {
+				assert.True(t, w.closed, "writer %d added but not closed", i)
+			}
+		}
+	}
+}
+
+func TestLog_WithLevelFile(t *testing.T) {
+	dir, other := t.TempDir(), t.TempDir()
+	warnPath := filepath.Join(other, "warn.log")
+	lg, err := NewLog(dir,
+		WithLevelFile(core.ErrorLevel, "error.log"),
+		WithLevelFile(core.WarnLevel, warnPath))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		levelFileKeyPrefix + filepath.Join(dir, "error.log"),
+		levelFileKeyPrefix + warnPath,
+	}, lg.(*Log).Writers())
+
+	lg.Info("info message")
+	lg.Warn("warn message")
+	lg.Errorw("error message", "user", "tom")
+	assert.NoError(t, lg.Close())
+
+	// 每个文件只写入[level, FatalLevel]之间的日志，主日志文件写入所有日志
+	data, err := os.ReadFile(filepath.Join(dir, "error.log"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "warn message")
+	assert.Contains(t, string(data), `msg="error message"`)
+	assert.Contains(t, string(data), "user=tom")
+	data, err = os.ReadFile(warnPath)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "info message")
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+	data, err = os.ReadFile(filepath.Join(dir, DefaultFilename))
+	assert.NoError(t, err)
+	for _, msg := range []string{"info message", "warn message", "error message"} {
+		assert.Contains(t, string(data), msg)
+	}
+}
+
+func TestLog_FilterWriter(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	alert := &bufWriter{}
+	assert.NoError(t, lg.SetWriter("alert", core.NewFilterWriter(alert, core.ErrorLevel, core.FatalLevel)))
+
+	// 只有ErrorLevel及以上的日志写入告警写入器
+	lg.Info("info message")
+	lg.Warnw("warn message", "user", "tom")
+	lg.Errorw("error message", "user", "tom")
+	assert.NotContains(t, alert.String(), "info message")
+	assert.NotContains(t, alert.String(), "warn message")
+	// WriteEntity的Message为原始消息，字段只在Fields中
+	assert.Equal(t, "error message\n", alert.String())
+	assert.NoError(t, lg.Close())
+}
+
+// entityRecorder 记录写入的结构化日志数据
+type entityRecorder struct {
+	bufWriter
+	entities []core.Entity
+}
+
+func (w *entityRecorder) WriteEntity(e core.Entity) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// Log在写入完成后复用Fields，需要拷贝
+	e.Fields = maps.Clone(e.Fields)
+	w.entities = append(w.entities, e)
+	return nil
+}
+
+func TestLog_TransformWriter(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rec := &entityRecorder{}
+	assert.NoError(t, lg.SetWriter("transform", core.NewTransformWriter(rec, core.ChainTransforms(
+		core.AddField("env", "prod"),
+		core.SetService("api"),
+		core.MaskField("password", "***"),
+		core.LevelMapper(map[core.LoggerLevel]core.LoggerLevel{core.WarnLevel: core.ErrorLevel}),
+	))))
+
+	lg.Infow("login", "user", "tom", "password", "123456")
+	lg.Warn("disk usage high")
+	assert.Len(t, rec.entities, 2)
+
+	e := rec.entities[0]
+	assert.Equal(t, core.InfoLevel, e.Level)
+	assert.Equal(t, "api", e.Service)
+	assert.Equal(t, map[string]any{"env": "prod", "user": "tom", "password": "***"}, e.Fields)
+	// 消息中不包含字段，脱敏之前的值不会泄露
+	assert.Equal(t, "login", e.Message)
+	assert.Equal(t, core.ErrorLevel, rec.entities[1].Level)
+	assert.NoError(t, lg.Close())
+}
+
+func TestLog_Logw(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithUTC(), WithLevel(core.InfoLevel))
+	assert.NoError(t, err)
+	defer lg.Close()
+	rh := &recordHook{levels: core.AllLevels()}
+	lg.AddHook(rh)
+
+	tl, ok := lg.(TimedLogger)
+	assert.True(t, ok)
+	ts := time.Date(2025, 5, 12, 12, 12, 0, 0, time.Local)
+	tl.Logw(ts, core.InfoLevel, "replayed", "user", "tom")
+	tl.Logw(time.Time{}, core.ErrorLevel, "failed")
+	tl.Logw(ts, core.DebugLevel, "ignored")
+
+	assert.Len(t, rh.entries, 2)
+	assert.True(t, ts.Equal(rh.entries[0].Time))
+	assert.Equal(t, time.UTC, rh.entries[0].Time.Location())
+	assert.Contains(t, rh.entries[0].Message, "replayed user=tom")
+	assert.False(t, rh.entries[1].Time.IsZero())
+	// ErrorLevel及以上的级别与Errorw一致，携带堆栈信息
+	assert.Contains(t, rh.entries[1].Message, "\n\t")
+}
+
+func TestLog_WithLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	assert.NoError(t, err)
+	lg, err := NewLog(t.TempDir(), WithLocation("America/New_York"))
+	assert.NoError(t, err)
+	defer lg.Close()
+	rh := &recordHook{levels: core.AllLevels()}
+	lg.AddHook(rh)
+
+	ts := time.Date(2025, 1, 12, 12, 12, 0, 0, time.UTC)
+	lg.(TimedLogger).Logw(ts, core.InfoLevel, "replayed")
+	lg.Info("now")
+	// WithUTC优先于WithLocation
+	lg.(*Log).Clone(WithUTC()).Info("utc")
+
+	assert.Len(t, rh.entries, 3)
+	assert.Equal(t, loc, rh.entries[0].Time.Location())
+	assert.True(t, ts.Equal(rh.entries[0].Time))
+	assert.Contains(t, rh.entries[0].Message, "2025-01-12T07:12:00-05:00")
+	assert.Equal(t, loc, rh.entries[1].Time.Location())
+	assert.Equal(t, time.UTC, rh.entries[2].Time.Location())
+}
+
+func TestLog_GELFStack(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+	w, err := core.NewGELFWriter("udp://" + conn.LocalAddr().String())
+	assert.NoError(t, err)
+
+	lg, err := NewLog(t.TempDir(), WithCallSkip(2))
+	assert.NoError(t, err)
+	assert.NoError(t, lg.SetWriter("gelf", w))
+	readMessage := func() map[string]any {
+		buf := make([]byte, core.GELFChunkSize)
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		assert.NoError(t, err)
+		var msg map[string]any
+		assert.NoError(t, json.Unmarshal(buf[:n], &msg))
+		return msg
+	}
+
+	// 异常级别的堆栈信息输出到full_message，第一帧是调用方
+	lg.Error("error message")
+	fullMessage, _ := readMessage()["full_message"].(string)
+	assert.Equal(t, 2, strings.Count(fullMessage, `"file":`))
+	assert.Contains(t, fullMessage, `"func":"TestLog_GELFStack"`)
+
+	lg.Info("info message")
+	assert.NotContains(t, readMessage(), "full_message")
+	assert.NoError(t, lg.Close())
+
+	// 配置了格式化器时同样携带堆栈信息
+	w, err = core.NewGELFWriter("udp://" + conn.LocalAddr().String())
+	assert.NoError(t, err)
+	lg, err = NewLog(t.TempDir(), WithCallSkip(2), WithLogfmtFormat())
+	assert.NoError(t, err)
+	assert.NoError(t, lg.SetWriter("gelf", w))
+	lg.Errorf("error %s", "message")
+	fullMessage, _ = readMessage()["full_message"].(string)
+	assert.Equal(t, 2, strings.Count(fullMessage, `"file":`))
+	assert.Contains(t, fullMessage, `"func":"TestLog_GELFStack"`)
+	assert.NoError(t, lg.Close())
+}
+
+func TestLog_FileOutput(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	lg, err := NewLog(dir, WithFileName("app.log"))
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "app.log")
+
+	lg.Info("first message")
+	lg.Error("error message")
+	assert.NoError(t, lg.Flush())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Contains(t, lines[0], "first message")
+	assert.Contains(t, lines[1], "error message")
+	// 日志文件不在Writers中
+	assert.Empty(t, lg.Writers())
+
+	// 外部工具移走日志文件后，RotateNow重新打开日志文件
+	assert.NoError(t, os.Rename(path, path+".1"))
+	assert.NoError(t, lg.(RotatableLogger).RotateNow())
+	lg.Info("after rotate")
+	assert.NoError(t, lg.Close())
+	// 关闭之后只输出到标准输出
+	lg.Info("after close")
+
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "after rotate")
+	assert.NotContains(t, string(data), "first message")
+	assert.NotContains(t, string(data), "after close")
+
+	// 日志目录无法创建时返回错误
+	_, err = NewLog(path)
+	assert.Error(t, err)
+}
+
+func TestLog_PrefixPool(t *testing.T) {
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	l := lg.(*Log)
+
+	var wg sync.WaitGroup
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				msg := l.buildMsg(l.now(), false, core.InfoLevel, fmt.Sprintf("goroutine %d message %d", i, j))
+				assert.True(t, strings.HasSuffix(msg, fmt.Sprintf("goroutine %d message %d", i, j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSprint(t *testing.T) {
+	assert.Equal(t, "hello", sprint("hello"))
+	assert.Equal(t, "1 2", sprint(1, 2))
+	assert.Equal(t, "hello", sprintf("hello"))
+	assert.Equal(t, "hello 1", sprintf("hello %d", 1))
+	assert.Equal(t, "100%", sprintf("100%%"))
+
+	msg := "hello world"
+	assert.Equal(t, float64(0), testing.AllocsPerRun(100, func() {
+		_ = sprint(msg)
+		_ = sprintf(msg)
+	}))
+}
+
+func BenchmarkLogInfoAlloc(b *testing.B) {
+	lg, err := NewLog(b.TempDir())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer lg.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lg.Info("hello world")
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/TimeWtr/logx/logrsink
+
+go 1.23.4
+
+require (
+	github.com/TimeWtr/logx v0.0.0-00010101000000-000000000000
+	github.com/go-logr/logr v1.4.2
+	github.com/stretchr/testify v1.10.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	golang.org/x/time v0.9.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/TimeWtr/logx => ../
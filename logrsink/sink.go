@@ -0,0 +1,185 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package logrsink 把logx适配为logr.LogSink，用于controller-runtime等基于logr的项目，
+// 独立的模块避免core用户引入logr依赖
+package logrsink
+
+import (
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/go-logr/logr"
+)
+
+const (
+	// NameKey logr名称的字段名，多级名称使用"/"连接
+	NameKey = "logger"
+	// ErrorKey Error方法中错误的字段名
+	ErrorKey = "error"
+	// missingValue 键值对数量为奇数时，最后一个key的值
+	missingValue = "<no-value>"
+	// sinkFrames Sink的方法在logr和logx之间增加的调用层级
+	sinkFrames = 1
+)
+
+type Options func(s *Sink)
+
+// WithVerbosityMap 自定义logr日志等级到logx日志级别的映射，未配置的日志等级使用默认映射：
+// 0映射为InfoLevel，大于0的映射为DebugLevel
+func WithVerbosityMap(m map[int]core.LoggerLevel) Options {
+	return func(s *Sink) {
+		for verbosity, level := range m {
+			s.levels[verbosity] = level
+		}
+	}
+}
+
+// Sink 实现logr.LogSink和logr.CallDepthLogSink接口，logr的日志等级越小越重要，按照映射转换为
+// logx的日志级别后调用logx.Logger对应级别的结构化方法写入。logx.Logger实现了logx.CallDepthLogger时
+// 跳过logr和Sink的调用层级，Hook和堆栈信息中的调用方为调用logr的位置
+type Sink struct {
+	lg logx.Logger
+	// logr名称，WithName添加的多级名称使用"/"连接
+	name string
+	// WithValues添加的键值对
+	values []any
+	// logr日志等级到logx日志级别的映射
+	levels map[int]core.LoggerLevel
+}
+
+func NewLogrSink(lg logx.Logger, opts ...Options) logr.LogSink {
+	s := &Sink{
+		lg:     lg,
+		levels: make(map[int]core.LoggerLevel),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Init 按照logr传入的调用深度跳过logr和Sink的调用层级
+func (s *Sink) Init(info logr.RuntimeInfo) {
+	s.lg = withCallDepth(s.lg, info.CallDepth+sinkFrames)
+}
+
+// WithCallDepth 额外跳过depth层调用方，用于封装了logr.Logger的辅助方法
+func (s *Sink) WithCallDepth(depth int) logr.LogSink {
+	ns := s.clone()
+	ns.lg = withCallDepth(s.lg, depth)
+	return ns
+}
+
+// withCallDepth lg没有实现logx.CallDepthLogger时无法调整调用层级，直接返回lg
+func withCallDepth(lg logx.Logger, depth int) logx.Logger {
+	if cl, ok := lg.(logx.CallDepthLogger); ok {
+		return cl.WithCallDepth(depth)
+	}
+
+	return lg
+}
+
+func (s *Sink) Enabled(level int) bool {
+	return s.lg.GetLevel().Enabled(s.toLevel(level))
+}
+
+func (s *Sink) Info(level int, msg string, keysAndValues ...any) {
+	kvs := s.keysAndValues(nil, keysAndValues)
+	switch s.toLevel(level) {
+	case core.TraceLevel:
+		s.lg.Tracew(msg, kvs...)
+	case core.DebugLevel:
+		s.lg.Debugw(msg, kvs...)
+	case core.InfoLevel:
+		s.lg.Infow(msg, kvs...)
+	case core.NoticeLevel:
+		s.lg.Noticew(msg, kvs...)
+	case core.WarnLevel:
+		s.lg.Warnw(msg, kvs...)
+	case core.ErrorLevel:
+		s.lg.Errorw(msg, kvs...)
+	case core.PanicLevel:
+		s.lg.Panicw(msg, kvs...)
+	case core.FatalLevel:
+		s.lg.Fatalw(msg, kvs...)
+	default:
+	}
+}
+
+// Error 总是使用ErrorLevel写入，err为空时不输出错误字段
+func (s *Sink) Error(err error, msg string, keysAndValues ...any) {
+	s.lg.Errorw(msg, s.keysAndValues(err, keysAndValues)...)
+}
+
+func (s *Sink) WithValues(keysAndValues ...any) logr.LogSink {
+	ns := s.clone()
+	ns.values = append(ns.values, keysAndValues...)
+	return ns
+}
+
+func (s *Sink) WithName(name string) logr.LogSink {
+	ns := s.clone()
+	if ns.name != "" {
+		ns.name += "/"
+	}
+	ns.name += name
+	return ns
+}
+
+func (s *Sink) clone() *Sink {
+	return &Sink{
+		lg:     s.lg,
+		name:   s.name,
+		values: append([]any(nil), s.values...),
+		levels: s.levels,
+	}
+}
+
+// toLevel 把logr的日志等级转换为logx的日志级别
+func (s *Sink) toLevel(verbosity int) core.LoggerLevel {
+	if level, ok := s.levels[verbosity]; ok {
+		return level
+	}
+	if verbosity <= 0 {
+		return core.InfoLevel
+	}
+
+	return core.DebugLevel
+}
+
+// keysAndValues 依次合并名称、错误、WithValues添加的键值对和本次传入的键值对，
+// 键值对数量为奇数时最后一个key的值为missingValue
+func (s *Sink) keysAndValues(err error, keysAndValues []any) []any {
+	kvs := make([]any, 0, len(s.values)+len(keysAndValues)+5)
+	if s.name != "" {
+		kvs = append(kvs, NameKey, s.name)
+	}
+	if err != nil {
+		kvs = append(kvs, ErrorKey, err)
+	}
+	kvs = appendPairs(kvs, s.values)
+	kvs = appendPairs(kvs, keysAndValues)
+
+	return kvs
+}
+
+func appendPairs(kvs, keysAndValues []any) []any {
+	kvs = append(kvs, keysAndValues...)
+	if len(keysAndValues)%2 == 1 {
+		kvs = append(kvs, missingValue)
+	}
+
+	return kvs
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logrsink
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	entries []logx.HookEntry
+}
+
+func (h *recordHook) Levels() []core.LoggerLevel {
+	return core.AllLevels()
+}
+
+func (h *recordHook) Fire(entry *logx.HookEntry) error {
+	h.entries = append(h.entries, *entry)
+	return nil
+}
+
+func TestSink(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir(), logx.WithLevel(core.InfoLevel))
+	assert.NoError(t, err)
+	rh := &recordHook{}
+	lg.AddHook(rh)
+
+	l := logr.New(NewLogrSink(lg)).WithName("controller").WithName("pod").WithValues("ns", "default")
+	assert.True(t, l.Enabled())
+	assert.False(t, l.V(1).Enabled())
+
+	l.Info("reconcile", "name", "web 1")
+	l.V(1).Info("ignored")
+	l.Error(errors.New("not found"), "failed", "retry")
+
+	assert.Len(t, rh.entries, 2)
+	assert.Equal(t, core.InfoLevel, rh.entries[0].Level)
+	assert.Contains(t, rh.entries[0].Message, `reconcile logger=controller/pod ns=default name="web 1"`)
+	assert.Equal(t, core.ErrorLevel, rh.entries[1].Level)
+	assert.Contains(t, rh.entries[1].Message,
+		`failed logger=controller/pod error="not found" ns=default retry=<no-value>`)
+
+	// 键值对作为结构化字段传递给logx
+	assert.Equal(t, "reconcile", rh.entries[0].RawMessage)
+	assert.Equal(t, []logx.Field{
+		logx.StringField(NameKey, "controller/pod"),
+		logx.StringField("ns", "default"),
+		logx.StringField("name", "web 1"),
+	}, rh.entries[0].Fields)
+	assert.Equal(t, logx.StringField(ErrorKey, "not found"), rh.entries[1].Fields[1])
+}
+
+// logHelper 封装logr.Logger的辅助方法，通过WithCallDepth跳过自身
+func logHelper(l logr.Logger, msg string) {
+	l.WithCallDepth(1).Info(msg)
+}
+
+func TestSink_CallDepth(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{}
+	lg.AddHook(rh)
+
+	l := logr.New(NewLogrSink(lg)).WithName("controller")
+	_, file, line, _ := runtime.Caller(0)
+	l.Info("direct")
+	logHelper(l, "helper")
+	l.Error(errors.New("not found"), "failed")
+
+	// 调用方为调用logr的位置，而不是logr或者Sink内部
+	assert.Len(t, rh.entries, 3)
+	for i, entry := range rh.entries {
+		assert.Equal(t, file, entry.Caller.File())
+		assert.Equal(t, line+1+i, entry.Caller.Line())
+	}
+}
+
+func TestSink_VerbosityMap(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir(), logx.WithLevel(core.DebugLevel))
+	assert.NoError(t, err)
+	rh := &recordHook{}
+	lg.AddHook(rh)
+
+	l := logr.New(NewLogrSink(lg, WithVerbosityMap(map[int]core.LoggerLevel{
+		0: core.WarnLevel,
+		2: core.InfoLevel,
+		3: core.NoticeLevel,
+	})))
+	l.Info("warn")
+	l.V(1).Info("debug")
+	l.V(2).Info("info")
+	l.V(3).Info("notice")
+
+	assert.Len(t, rh.entries, 4)
+	assert.Equal(t, core.WarnLevel, rh.entries[0].Level)
+	assert.Equal(t, core.DebugLevel, rh.entries[1].Level)
+	assert.Equal(t, core.InfoLevel, rh.entries[2].Level)
+	assert.Equal(t, core.NoticeLevel, rh.entries[3].Level)
+}
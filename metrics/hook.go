@@ -0,0 +1,111 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics 日志相关的Prometheus指标，独立的模块避免core用户引入Prometheus依赖
+package metrics
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// EntriesTotalName 日志条数指标的名称
+	EntriesTotalName = "logx_log_entries_total"
+	// EntriesTotalHelp 日志条数指标的说明
+	EntriesTotalHelp = "Total number of log entries by level"
+)
+
+// PrometheusHook 按照日志级别统计日志条数的Hook，指标为logx_log_entries_total{level="error"}
+type PrometheusHook struct {
+	// 指标注册器
+	reg prometheus.Registerer
+	// 日志条数计数器
+	counter *prometheus.CounterVec
+	// 延迟注册，首次触发时注册
+	once sync.Once
+	// 注册的错误，注册失败之后不再统计
+	err error
+	// 指标是否由Hook注册，复用已有的指标时为false
+	owned bool
+}
+
+// NewPrometheusHook 创建Prometheus Hook，指标在第一次触发时注册到reg，
+// 如果reg中已经注册了同名指标则复用已有的指标，方便在测试中使用独立的注册器
+func NewPrometheusHook(reg prometheus.Registerer) *PrometheusHook {
+	return &PrometheusHook{
+		reg: reg,
+		counter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: EntriesTotalName,
+			Help: EntriesTotalHelp,
+		}, []string{"level"}),
+	}
+}
+
+func (h *PrometheusHook) Levels() []core.LoggerLevel {
+	return core.AllLevels()
+}
+
+// Fire 注册失败时只在第一次触发时返回错误，避免每条日志都输出同样的错误，之后的触发不做任何处理
+func (h *PrometheusHook) Fire(entry *logx.HookEntry) error {
+	var err error
+	h.once.Do(func() {
+		h.register()
+		err = h.err
+	})
+	if err != nil {
+		return err
+	}
+	if h.err != nil {
+		return nil
+	}
+
+	h.counter.WithLabelValues(entry.Level.String()).Inc()
+	return nil
+}
+
+// register 注册指标，使用reg.Register代替MustRegister，避免重复注册时panic
+func (h *PrometheusHook) register() {
+	err := h.reg.Register(h.counter)
+	if err == nil {
+		h.owned = true
+		return
+	}
+
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(*prometheus.CounterVec); ok {
+			h.counter = existing
+			return
+		}
+	}
+
+	h.err = err
+}
+
+// Unregister 从注册器中移除Hook注册的指标，复用已有的指标或者还没有注册时不会移除，返回false，
+// 在第一次触发之前调用时，之后的触发也不会再注册指标
+func (h *PrometheusHook) Unregister() bool {
+	// 通过once与第一次触发的注册同步，之后读取counter和owned不会产生数据竞争
+	h.once.Do(func() {})
+	if !h.owned {
+		return false
+	}
+
+	return h.reg.Unregister(h.counter)
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusHook(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	h := NewPrometheusHook(reg)
+	assert.Equal(t, core.AllLevels(), h.Levels())
+
+	lg, err := logx.NewLog(t.TempDir(), logx.WithLevel(core.DebugLevel))
+	assert.NoError(t, err)
+	lg.AddHook(h)
+
+	lg.Debug("debug message")
+	lg.Info("info message")
+	lg.Error("error message")
+	lg.Errorf("error %s", "message")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.counter.WithLabelValues("debug")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(h.counter.WithLabelValues("info")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(h.counter.WithLabelValues("error")))
+
+	// 同一个注册器上重复注册时复用已有的指标
+	h2 := NewPrometheusHook(reg)
+	assert.NoError(t, h2.Fire(&logx.HookEntry{Level: core.ErrorLevel}))
+	assert.Equal(t, float64(3), testutil.ToFloat64(h.counter.WithLabelValues("error")))
+
+	// 复用的指标不属于h2，不会被h2移除
+	assert.False(t, h2.Unregister())
+	count, err := testutil.GatherAndCount(reg, EntriesTotalName)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	assert.True(t, h.Unregister())
+	count, err = testutil.GatherAndCount(reg, EntriesTotalName)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
+func TestPrometheusHook_RegisterFailed(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	// 同名但是标签不同的指标导致注册失败
+	reg.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{Name: EntriesTotalName, Help: EntriesTotalHelp}))
+	h := NewPrometheusHook(reg)
+
+	// 只在第一次触发时返回错误，之后不再统计
+	assert.Error(t, h.Fire(&logx.HookEntry{Level: core.InfoLevel}))
+	assert.NoError(t, h.Fire(&logx.HookEntry{Level: core.InfoLevel}))
+	assert.Equal(t, float64(0), testutil.ToFloat64(h.counter.WithLabelValues("info")))
+	assert.False(t, h.Unregister())
+}
+
+func TestPrometheusHook_UnregisterBeforeFire(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	h := NewPrometheusHook(reg)
+
+	// 还没有注册时不会移除，之后的触发也不会再注册
+	assert.False(t, h.Unregister())
+	assert.NoError(t, h.Fire(&logx.HookEntry{Level: core.InfoLevel}))
+	count, err := testutil.GatherAndCount(reg, EntriesTotalName)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,287 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/TimeWtr/logx/core"
+)
+
+// MultiLog 组合多个Logger，日志会分发给所有的Logger，比如同时写入文件、终端和远程服务，
+// MultiLog本身没有日志级别，由每个Logger按照自己的日志级别过滤
+type MultiLog struct {
+	loggers []Logger
+}
+
+// NewMultiLog 创建组合Logger，为空的Logger会被忽略
+func NewMultiLog(loggers ...Logger) Logger {
+	m := &MultiLog{
+		loggers: make([]Logger, 0, len(loggers)),
+	}
+	for _, lg := range loggers {
+		if lg != nil {
+			m.loggers = append(m.loggers, lg)
+		}
+	}
+
+	return m
+}
+
+func (m *MultiLog) Trace(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Trace(v...)
+	}
+}
+
+func (m *MultiLog) Debug(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Debug(v...)
+	}
+}
+
+func (m *MultiLog) Info(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Info(v...)
+	}
+}
+
+func (m *MultiLog) Notice(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Notice(v...)
+	}
+}
+
+func (m *MultiLog) Warn(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Warn(v...)
+	}
+}
+
+func (m *MultiLog) Error(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Error(v...)
+	}
+}
+
+func (m *MultiLog) Panic(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Panic(v...)
+	}
+}
+
+func (m *MultiLog) Fatal(v ...any) {
+	for _, lg := range m.loggers {
+		lg.Fatal(v...)
+	}
+}
+
+func (m *MultiLog) Tracef(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Tracef(format, v...)
+	}
+}
+
+func (m *MultiLog) Debugf(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Debugf(format, v...)
+	}
+}
+
+func (m *MultiLog) Infof(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Infof(format, v...)
+	}
+}
+
+func (m *MultiLog) Noticef(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Noticef(format, v...)
+	}
+}
+
+func (m *MultiLog) Warnf(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Warnf(format, v...)
+	}
+}
+
+func (m *MultiLog) Errorf(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Errorf(format, v...)
+	}
+}
+
+func (m *MultiLog) Panicf(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Panicf(format, v...)
+	}
+}
+
+func (m *MultiLog) Fatalf(format string, v ...any) {
+	for _, lg := range m.loggers {
+		lg.Fatalf(format, v...)
+	}
+}
+
+func (m *MultiLog) Tracew(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Tracew(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Debugw(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Debugw(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Infow(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Infow(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Noticew(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Noticew(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Warnw(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Warnw(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Errorw(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Errorw(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Panicw(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Panicw(msg, keysAndValues...)
+	}
+}
+
+func (m *MultiLog) Fatalw(msg string, keysAndValues ...any) {
+	for _, lg := range m.loggers {
+		lg.Fatalw(msg, keysAndValues...)
+	}
+}
+
+// SetLevel 修改所有Logger的日志级别
+func (m *MultiLog) SetLevel(level core.LoggerLevel) {
+	for _, lg := range m.loggers {
+		lg.SetLevel(level)
+	}
+}
+
+// GetLevel 返回所有Logger中最低的日志级别，即至少有一个Logger会输出的级别，
+// 没有Logger时返回默认的InfoLevel
+func (m *MultiLog) GetLevel() core.LoggerLevel {
+	if len(m.loggers) == 0 {
+		return core.InfoLevel
+	}
+
+	level := m.loggers[0].GetLevel()
+	for _, lg := range m.loggers[1:] {
+		level = min(level, lg.GetLevel())
+	}
+
+	return level
+}
+
+// AddHook 给所有的Logger添加Hook，同一条日志会在每个Logger中分别触发一次
+func (m *MultiLog) AddHook(h Hook) {
+	for _, lg := range m.loggers {
+		lg.AddHook(h)
+	}
+}
+
+func (m *MultiLog) RemoveHook(h Hook) {
+	for _, lg := range m.loggers {
+		lg.RemoveHook(h)
+	}
+}
+
+// Flush 刷新所有的Logger，某个Logger失败时继续刷新其他的Logger，通过errors.Join聚合所有的错误
+func (m *MultiLog) Flush() error {
+	return m.each(Logger.Flush)
+}
+
+// SetWriter 给所有的Logger添加写入器，同一条日志会被每个Logger分别写入一次
+func (m *MultiLog) SetWriter(key string, w core.Writer) error {
+	return m.each(func(lg Logger) error {
+		return lg.SetWriter(key, w)
+	})
+}
+
+func (m *MultiLog) RemoveWriter(key string) {
+	for _, lg := range m.loggers {
+		lg.RemoveWriter(key)
+	}
+}
+
+// Writers 返回所有Logger的写入器key，已经去重
+func (m *MultiLog) Writers() []string {
+	var keys []string
+	for _, lg := range m.loggers {
+		for _, key := range lg.Writers() {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+// WithError 返回由每个Logger的子Logger组成的MultiLog，err为nil时返回自身
+func (m *MultiLog) WithError(err error) Logger {
+	if err == nil {
+		return m
+	}
+
+	child := &MultiLog{loggers: make([]Logger, 0, len(m.loggers))}
+	for _, lg := range m.loggers {
+		child.loggers = append(child.loggers, lg.WithError(err))
+	}
+
+	return child
+}
+
+func (m *MultiLog) Discard() Logger {
+	return NewNopLogger()
+}
+
+// Close 关闭所有的Logger，某个Logger失败时继续关闭其他的Logger
+func (m *MultiLog) Close() error {
+	return m.each(Logger.Close)
+}
+
+func (m *MultiLog) each(fn func(lg Logger) error) error {
+	var errs []error
+	for _, lg := range m.loggers {
+		if err := fn(lg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+type flushErrLogger struct {
+	NopLogger
+	flushed bool
+	err     error
+}
+
+func (l *flushErrLogger) Flush() error {
+	l.flushed = true
+	return l.err
+}
+
+func TestMultiLog(t *testing.T) {
+	debug, warn := NewTestLogger(), NewTestLogger()
+	warn.SetLevel(core.WarnLevel)
+	lg := NewMultiLog(debug, nil, warn)
+
+	// 每个Logger按照自己的日志级别过滤
+	lg.Debug("debug message")
+	lg.Infof("info %s", "message")
+	lg.Error("error message")
+	assert.Len(t, debug.Entries(), 3)
+	assert.Len(t, warn.Entries(), 1)
+	assert.True(t, warn.Contains(core.ErrorLevel, "error message"))
+	assert.Equal(t, core.TraceLevel, lg.GetLevel())
+
+	lg.SetLevel(core.ErrorLevel)
+	assert.Equal(t, core.ErrorLevel, debug.GetLevel())
+	assert.Equal(t, core.ErrorLevel, lg.GetLevel())
+	assert.Equal(t, core.InfoLevel, NewMultiLog().GetLevel())
+}
+
+func TestMultiLog_WithError(t *testing.T) {
+	first, second := NewTestLogger(), NewTestLogger()
+	lg := NewMultiLog(first, second)
+	assert.Same(t, lg, lg.WithError(nil))
+
+	lg.WithError(errors.New("mock error")).Info("info message")
+	for _, tl := range []*TestLogger{first, second} {
+		assert.Len(t, tl.Entries(), 1)
+		assert.Equal(t, []Field{ErrorField(errors.New("mock error"))}, tl.Entries()[0].Fields)
+	}
+}
+
+func TestMultiLog_Flush(t *testing.T) {
+	err1, err2 := errors.New("mock error 1"), errors.New("mock error 2")
+	l1 := &flushErrLogger{err: err1}
+	l2 := &flushErrLogger{}
+	l3 := &flushErrLogger{err: err2}
+
+	err := NewMultiLog(l1, l2, l3).Flush()
+	assert.ErrorIs(t, err, err1)
+	assert.ErrorIs(t, err, err2)
+	// 某个Logger失败时其他的Logger仍然会被刷新
+	assert.True(t, l1.flushed && l2.flushed && l3.flushed)
+	assert.NoError(t, NewMultiLog(l2).Flush())
+}
+
+func TestMultiLog_Writers(t *testing.T) {
+	l1, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	l2, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	lg := NewMultiLog(l1, l2)
+
+	w := &bufWriter{}
+	assert.NoError(t, lg.SetWriter("buf", w))
+	assert.Equal(t, []string{"buf"}, lg.Writers())
+	assert.Equal(t, []string{"buf"}, l2.Writers())
+	// 每个Logger分别写入一次
+	lg.Info("message")
+	assert.Equal(t, 2, strings.Count(w.String(), "message"))
+
+	lg.RemoveWriter("buf")
+	assert.Empty(t, lg.Writers())
+	assert.NoError(t, lg.Close())
+	assert.ErrorIs(t, lg.SetWriter("buf", w), errorx.ErrBufferClose)
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import "github.com/TimeWtr/logx/core"
+
+// NopLogger 丢弃所有日志的Logger，用于测试和基准测试中不关心日志输出的场景
+type NopLogger struct{}
+
+func NewNopLogger() Logger {
+	return NopLogger{}
+}
+
+func (NopLogger) Trace(_ ...any) {}
+
+func (NopLogger) Debug(_ ...any) {}
+
+func (NopLogger) Info(_ ...any) {}
+
+func (NopLogger) Notice(_ ...any) {}
+
+func (NopLogger) Warn(_ ...any) {}
+
+func (NopLogger) Error(_ ...any) {}
+
+func (NopLogger) Panic(_ ...any) {}
+
+func (NopLogger) Fatal(_ ...any) {}
+
+func (NopLogger) Tracef(_ string, _ ...any) {}
+
+func (NopLogger) Debugf(_ string, _ ...any) {}
+
+func (NopLogger) Infof(_ string, _ ...any) {}
+
+func (NopLogger) Noticef(_ string, _ ...any) {}
+
+func (NopLogger) Warnf(_ string, _ ...any) {}
+
+func (NopLogger) Errorf(_ string, _ ...any) {}
+
+func (NopLogger) Panicf(_ string, _ ...any) {}
+
+func (NopLogger) Fatalf(_ string, _ ...any) {}
+
+func (NopLogger) Tracew(_ string, _ ...any) {}
+
+func (NopLogger) Debugw(_ string, _ ...any) {}
+
+func (NopLogger) Infow(_ string, _ ...any) {}
+
+func (NopLogger) Noticew(_ string, _ ...any) {}
+
+func (NopLogger) Warnw(_ string, _ ...any) {}
+
+func (NopLogger) Errorw(_ string, _ ...any) {}
+
+func (NopLogger) Panicw(_ string, _ ...any) {}
+
+func (NopLogger) Fatalw(_ string, _ ...any) {}
+
+// SetLevel 日志级别不会被修改
+func (NopLogger) SetLevel(_ core.LoggerLevel) {}
+
+// GetLevel 总是返回默认的InfoLevel
+func (NopLogger) GetLevel() core.LoggerLevel {
+	return core.InfoLevel
+}
+
+// AddHook 日志被丢弃，Hook不会被触发
+func (NopLogger) AddHook(_ Hook) {}
+
+func (NopLogger) RemoveHook(_ Hook) {}
+
+func (NopLogger) Flush() error {
+	return nil
+}
+
+// SetWriter 日志被丢弃，写入器不会被添加
+func (NopLogger) SetWriter(_ string, _ core.Writer) error {
+	return nil
+}
+
+func (NopLogger) RemoveWriter(_ string) {}
+
+func (NopLogger) Writers() []string {
+	return nil
+}
+
+func (n NopLogger) WithError(_ error) Logger {
+	return n
+}
+
+func (n NopLogger) Discard() Logger {
+	return n
+}
+
+func (NopLogger) Close() error {
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNopLogger(t *testing.T) {
+	lg := NewNopLogger()
+	rh := &recordHook{levels: core.AllLevels()}
+	lg.AddHook(rh)
+
+	lg.Info("info message")
+	lg.Noticew("notice message", "user", "tom")
+	lg.Errorf("error %s", "message")
+	lg.Fatal("fatal message")
+	assert.Empty(t, rh.entries)
+
+	lg.SetLevel(core.ErrorLevel)
+	assert.Equal(t, core.InfoLevel, lg.GetLevel())
+	assert.Equal(t, lg, lg.WithError(errors.New("mock error")))
+	assert.Equal(t, lg, lg.Discard())
+	assert.NoError(t, lg.Flush())
+	lg.RemoveHook(rh)
+
+	assert.NoError(t, lg.SetWriter("nop", nil))
+	assert.Empty(t, lg.Writers())
+	lg.RemoveWriter("nop")
+	assert.NoError(t, lg.Close())
+}
+
+func BenchmarkNopLogger(b *testing.B) {
+	lg := NewNopLogger()
+	for i := 0; i < b.N; i++ {
+		lg.Infof("info %d", i)
+	}
+}
@@ -14,10 +14,19 @@
 
 package logx
 
-import "github.com/TimeWtr/logx/core"
+import (
+	"slices"
+
+	"github.com/TimeWtr/logx/core"
+)
 
 type Options func(*Config)
 
+// WithValidation 显式声明配置校验，NewLog总是会校验配置，该选项不做任何修改
+func WithValidation() Options {
+	return func(_ *Config) {}
+}
+
 // WithColor 是否开启日志输出颜色
 func WithColor() Options {
 	return func(l *Config) {
@@ -25,6 +34,21 @@ func WithColor() Options {
 	}
 }
 
+// WithColorMap 自定义日志级别的输出颜色，支持core.Color、core.Color256和core.RGBColor，
+// 未设置的日志级别使用默认颜色
+func WithColorMap(m map[core.LoggerLevel]core.Colorizer) Options {
+	return func(l *Config) {
+		l.colorMap = m
+	}
+}
+
+// WithLogfmtFormat 使用logfmt格式输出日志
+func WithLogfmtFormat() Options {
+	return func(l *Config) {
+		l.formatter = core.NewLogfmtFormatter()
+	}
+}
+
 // WithLevel 设置日志级别，如果不设置，默认级别是InfoLevel
 func WithLevel(level core.LoggerLevel) Options {
 	return func(l *Config) {
@@ -39,6 +63,16 @@ func WithFileName(fileName string) Options {
 	}
 }
 
+// WithLevelFile 把[level, FatalLevel]之间的日志额外写入path指定的文件，比如把ErrorLevel及以上的
+// 日志单独写入一个文件用于告警，多次调用写入多个文件。path为相对路径时相对于日志文件的保存路径，
+// 文件使用配置的格式化器，没有配置时使用logfmt格式。只有NewLog和NewLogWithConfig会打开文件，Clone时不生效
+func WithLevelFile(level core.LoggerLevel, path string) Options {
+	return func(l *Config) {
+		// Clip之后追加时重新分配，Clone拷贝的配置不会写入共享的底层数组
+		l.levelFiles = append(slices.Clip(l.levelFiles), levelFile{level: level, path: path})
+	}
+}
+
 // WithLine 开启日志打印行号
 func WithLine(enable bool) Options {
 	return func(l *Config) {
@@ -53,6 +87,14 @@ func WithCallSkip(skip int) Options {
 	}
 }
 
+// WithCallDepth 设置获取调用方时额外跳过的层级，logx被中间件等其他库封装时，设置为封装的层数，
+// Hook中的调用方和异常级别的堆栈信息从真正的调用方开始
+func WithCallDepth(extraDepth int) Options {
+	return func(l *Config) {
+		l.callDepth = extraDepth
+	}
+}
+
 // WithAsync 开启异步写入日志文件
 func WithAsync() Options {
 	return func(l *Config) {
@@ -67,6 +109,27 @@ func WithLocation(location string) Options {
 	}
 }
 
+// WithTimestampFormat 设置日志时间的格式，默认为time.RFC3339Nano
+func WithTimestampFormat(layout string) Options {
+	return func(l *Config) {
+		l.timeLayout = layout
+	}
+}
+
+// WithUTC 日志时间使用UTC时间，优先于WithLocation
+func WithUTC() Options {
+	return func(l *Config) {
+		l.enableUTC = true
+	}
+}
+
+// WithMonotonicClock 去除日志时间中的单调时钟读数，单调时钟只在单个进程内有意义
+func WithMonotonicClock() Options {
+	return func(l *Config) {
+		l.stripMonotonic = true
+	}
+}
+
 // WithThreshold 设置单个文件的大小，单位为MB，默认为100MB
 func WithThreshold(threshold int64) Options {
 	return func(l *Config) {
@@ -0,0 +1,186 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelbridge 把logx的日志通过Hook桥接到OpenTelemetry的日志信号，配合OTLP导出器
+// 可以在Grafana Tempo、Honeycomb、Jaeger等后端关联链路和日志，独立的模块避免core用户引入otel依赖。
+// Hook按照添加的顺序触发，桥接的Hook需要在logx.RedactionHook、logx.SamplingHook等修改或者丢弃
+// 日志的Hook之后添加，否则发送的是脱敏之前的内容，被采样丢弃的日志也会发送
+package otelbridge
+
+import (
+	"context"
+	"time"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName 默认的instrumentation scope名称
+	ScopeName = "github.com/TimeWtr/logx"
+	// TraceIDKey 链路ID的字段名，值为32位十六进制字符串时作为日志记录的TraceID，不再作为属性输出
+	TraceIDKey = "trace_id"
+)
+
+type Options func(h *Hook)
+
+// WithLevels 需要桥接的日志级别，默认桥接全部的内置日志级别
+func WithLevels(levels ...core.LoggerLevel) Options {
+	return func(h *Hook) {
+		h.levels = levels
+	}
+}
+
+// WithScopeName 自定义instrumentation scope名称
+func WithScopeName(name string) Options {
+	return func(h *Hook) {
+		h.scope = name
+	}
+}
+
+// Hook 实现logx.Hook接口，把HookEntry转换为log.Record后通过log.Logger发送，
+// 只读取entry的内容，不会修改或者丢弃日志。只能看到之前添加的Hook修改之后的内容，
+// 需要在RedactionHook和SamplingHook之后添加
+type Hook struct {
+	lg log.Logger
+	// 需要桥接的日志级别
+	levels []core.LoggerLevel
+	// instrumentation scope名称
+	scope string
+}
+
+func NewHook(provider log.LoggerProvider, opts ...Options) *Hook {
+	h := &Hook{
+		levels: core.AllLevels(),
+		scope:  ScopeName,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.lg = provider.Logger(h.scope)
+
+	return h
+}
+
+// WithOTLP 创建Hook并添加到lg，返回的Hook可以用于RemoveHook，需要在添加RedactionHook和
+// SamplingHook之后调用
+func WithOTLP(lg logx.Logger, provider log.LoggerProvider, opts ...Options) *Hook {
+	h := NewHook(provider, opts...)
+	lg.AddHook(h)
+
+	return h
+}
+
+func (h *Hook) Levels() []core.LoggerLevel {
+	return h.levels
+}
+
+func (h *Hook) Fire(entry *logx.HookEntry) error {
+	ctx := context.Background()
+	severity := Severity(entry.Level)
+	if !h.lg.Enabled(ctx, log.EnabledParameters{Severity: severity}) {
+		return nil
+	}
+
+	var r log.Record
+	r.SetTimestamp(entry.Time)
+	r.SetObservedTimestamp(time.Now())
+	r.SetSeverity(severity)
+	r.SetSeverityText(entry.Level.UpperString())
+	r.SetBody(log.StringValue(body(entry)))
+	for _, f := range entry.Fields {
+		if f.Key == TraceIDKey {
+			if id, err := trace.TraceIDFromHex(f.String()); err == nil {
+				ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{TraceID: id}))
+				continue
+			}
+		}
+		r.AddAttributes(attribute(f))
+	}
+	h.lg.Emit(ctx, r)
+
+	return nil
+}
+
+// body 使用不带时间、级别前缀、字段和堆栈信息的日志内容作为Body，字段已经作为属性输出，
+// 手动构造的HookEntry没有RawMessage时使用Message
+func body(entry *logx.HookEntry) string {
+	if entry.RawMessage != "" {
+		return entry.RawMessage
+	}
+
+	return entry.Message
+}
+
+// Severity 把logx的日志级别转换为OpenTelemetry的日志严重程度，自定义级别返回log.SeverityUndefined
+func Severity(level core.LoggerLevel) log.Severity {
+	switch level {
+	case core.TraceLevel:
+		return log.SeverityTrace
+	case core.DebugLevel:
+		return log.SeverityDebug
+	case core.InfoLevel:
+		return log.SeverityInfo
+	case core.NoticeLevel:
+		return log.SeverityInfo2
+	case core.WarnLevel:
+		return log.SeverityWarn
+	case core.ErrorLevel:
+		return log.SeverityError
+	case core.PanicLevel:
+		return log.SeverityError4
+	case core.FatalLevel:
+		return log.SeverityFatal
+	default:
+		return log.SeverityUndefined
+	}
+}
+
+// attribute 按照字段值的类型转换为属性，无法直接表示的类型使用字段的字符串格式
+func attribute(f logx.Field) log.KeyValue {
+	switch v := f.Value.(type) {
+	case nil:
+		return log.Empty(f.Key)
+	case string:
+		return log.String(f.Key, v)
+	case bool:
+		return log.Bool(f.Key, v)
+	case int:
+		return log.Int(f.Key, v)
+	case int8:
+		return log.Int64(f.Key, int64(v))
+	case int16:
+		return log.Int64(f.Key, int64(v))
+	case int32:
+		return log.Int64(f.Key, int64(v))
+	case int64:
+		return log.Int64(f.Key, v)
+	case uint8:
+		return log.Int64(f.Key, int64(v))
+	case uint16:
+		return log.Int64(f.Key, int64(v))
+	case uint32:
+		return log.Int64(f.Key, int64(v))
+	case float32:
+		return log.Float64(f.Key, float64(v))
+	case float64:
+		return log.Float64(f.Key, v)
+	case []byte:
+		return log.Bytes(f.Key, v)
+	default:
+		return log.String(f.Key, f.String())
+	}
+}
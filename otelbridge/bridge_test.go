@@ -0,0 +1,98 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otelbridge
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHook(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir(), logx.WithLevel(core.TraceLevel))
+	assert.NoError(t, err)
+	rec := logtest.NewRecorder()
+	WithOTLP(lg, rec, WithLevels(core.InfoLevel, core.WarnLevel))
+
+	const traceID = "4bf92f3577b34da6a50b6b61d7d8e3e1"
+	lg.Infow("request done", TraceIDKey, traceID, "status", 200, "ok", true)
+	lg.Warnw("slow request", TraceIDKey, "invalid")
+	lg.Debug("not bridged")
+
+	result := rec.Result()
+	assert.Len(t, result, 1)
+	assert.Equal(t, ScopeName, result[0].Name)
+	records := result[0].Records
+	assert.Len(t, records, 2)
+
+	r := records[0]
+	assert.Equal(t, log.SeverityInfo, r.Severity())
+	assert.Equal(t, "INFO", r.SeverityText())
+	// Body不包括时间、级别前缀和字段
+	assert.Equal(t, "request done", r.Body().AsString())
+	id, _ := trace.TraceIDFromHex(traceID)
+	assert.Equal(t, id, trace.SpanContextFromContext(r.Context()).TraceID())
+	attrs := map[string]log.Value{}
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	assert.Len(t, attrs, 2)
+	assert.Equal(t, int64(200), attrs["status"].AsInt64())
+	assert.True(t, attrs["ok"].AsBool())
+
+	// 无法解析的链路ID作为普通属性输出
+	r = records[1]
+	assert.Equal(t, log.SeverityWarn, r.Severity())
+	assert.False(t, trace.SpanContextFromContext(r.Context()).TraceID().IsValid())
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		assert.Equal(t, TraceIDKey, kv.Key)
+		assert.Equal(t, "invalid", kv.Value.AsString())
+		return true
+	})
+}
+
+func TestHook_AfterRedaction(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir())
+	assert.NoError(t, err)
+	// 在脱敏Hook之后添加，发送的是脱敏之后的内容
+	lg.AddHook(logx.NewRedactionHook([]*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}`)}, "[REDACTED]"))
+	rec := logtest.NewRecorder()
+	WithOTLP(lg, rec)
+
+	lg.Infow("card 1234-5678", "card", "1234-5678")
+	records := rec.Result()[0].Records
+	assert.Len(t, records, 1)
+	assert.Equal(t, "card [REDACTED]", records[0].Body().AsString())
+	records[0].WalkAttributes(func(kv log.KeyValue) bool {
+		assert.Equal(t, "[REDACTED]", kv.Value.AsString())
+		return true
+	})
+}
+
+func TestSeverity(t *testing.T) {
+	assert.Equal(t, log.SeverityTrace, Severity(core.TraceLevel))
+	assert.Equal(t, log.SeverityInfo2, Severity(core.NoticeLevel))
+	assert.Equal(t, log.SeverityError, Severity(core.ErrorLevel))
+	assert.Equal(t, log.SeverityError4, Severity(core.PanicLevel))
+	assert.Equal(t, log.SeverityFatal, Severity(core.FatalLevel))
+	assert.Equal(t, log.SeverityUndefined, Severity(core.LoggerLevel(0)))
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proto 提供core.Entity的protobuf序列化格式，entity.pb.go由entity.proto生成，
+// 独立的模块避免core用户引入protobuf依赖
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative entity.proto
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Marshal 把core.Entity序列化为protobuf格式
+func Marshal(e core.Entity) ([]byte, error) {
+	return proto.Marshal(ToProto(e))
+}
+
+// Unmarshal 把protobuf格式的数据反序列化为core.Entity
+func Unmarshal(b []byte) (core.Entity, error) {
+	p := &LogEntry{}
+	if err := proto.Unmarshal(b, p); err != nil {
+		return core.Entity{}, err
+	}
+
+	return FromProto(p), nil
+}
+
+// ToProto 把core.Entity转换为LogEntry，结构化字段按照key排序，保证相同的日志序列化结果一致
+func ToProto(e core.Entity) *LogEntry {
+	p := &LogEntry{
+		Timestamp: timestamppb.New(time.Unix(0, e.Timestamp)),
+		Level:     toLevel(e.Level),
+		TraceId:   e.TraceID,
+		Service:   e.Service,
+		Message:   e.Message,
+	}
+
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	p.Fields = make([]*KeyValue, 0, len(keys))
+	for _, key := range keys {
+		p.Fields = append(p.Fields, &KeyValue{Key: key, Value: toValue(e.Fields[key])})
+	}
+
+	p.Callers = make([]*Caller, 0, len(e.CE))
+	for _, ce := range e.CE {
+		p.Callers = append(p.Callers, &Caller{
+			File:     ce.File(),
+			Line:     int64(ce.Line()),
+			Function: ce.Func(),
+		})
+	}
+
+	return p
+}
+
+// FromProto 把LogEntry转换为core.Entity，堆栈信息中没有pc，无法还原方法名称
+func FromProto(p *LogEntry) core.Entity {
+	e := core.Entity{
+		Level:   fromLevel(p.GetLevel()),
+		TraceID: p.GetTraceId(),
+		Service: p.GetService(),
+		Message: p.GetMessage(),
+	}
+	if p.GetTimestamp() != nil {
+		e.Timestamp = p.GetTimestamp().AsTime().UnixNano()
+	}
+
+	if len(p.GetFields()) > 0 {
+		e.Fields = make(map[string]any, len(p.GetFields()))
+		for _, kv := range p.GetFields() {
+			e.Fields[kv.GetKey()] = fromValue(kv.GetValue())
+		}
+	}
+
+	for _, c := range p.GetCallers() {
+		e.CE = append(e.CE, core.NewCallerEntity(c.GetFile(), int(c.GetLine())))
+	}
+
+	return e
+}
+
+// toValue 按照字段值的类型转换为Value，无法直接表示的类型使用字符串格式
+func toValue(v any) *Value {
+	switch val := v.(type) {
+	case nil:
+		return &Value{}
+	case string:
+		return &Value{Kind: &Value_StringValue{StringValue: val}}
+	case error:
+		return &Value{Kind: &Value_StringValue{StringValue: val.Error()}}
+	case bool:
+		return &Value{Kind: &Value_BoolValue{BoolValue: val}}
+	case int:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case int8:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case int16:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case int32:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case int64:
+		return &Value{Kind: &Value_IntValue{IntValue: val}}
+	case uint8:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case uint16:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case uint32:
+		return &Value{Kind: &Value_IntValue{IntValue: int64(val)}}
+	case uint:
+		return uintValue(uint64(val))
+	case uint64:
+		return uintValue(val)
+	case float32:
+		return &Value{Kind: &Value_DoubleValue{DoubleValue: float64(val)}}
+	case float64:
+		return &Value{Kind: &Value_DoubleValue{DoubleValue: val}}
+	case []byte:
+		return &Value{Kind: &Value_BytesValue{BytesValue: val}}
+	case time.Time:
+		return &Value{Kind: &Value_TimeValue{TimeValue: timestamppb.New(val)}}
+	default:
+		return &Value{Kind: &Value_StringValue{StringValue: fmt.Sprint(val)}}
+	}
+}
+
+// uintValue 超过int64范围的无符号整数使用字符串格式，避免溢出为负数
+func uintValue(v uint64) *Value {
+	if v > math.MaxInt64 {
+		return &Value{Kind: &Value_StringValue{StringValue: fmt.Sprint(v)}}
+	}
+
+	return &Value{Kind: &Value_IntValue{IntValue: int64(v)}}
+}
+
+// fromValue 把Value转换为Go的值，整数统一为int64，时间为time.Time，空值为nil
+func fromValue(v *Value) any {
+	switch kind := v.GetKind().(type) {
+	case *Value_StringValue:
+		return kind.StringValue
+	case *Value_IntValue:
+		return kind.IntValue
+	case *Value_DoubleValue:
+		return kind.DoubleValue
+	case *Value_BoolValue:
+		return kind.BoolValue
+	case *Value_BytesValue:
+		return kind.BytesValue
+	case *Value_TimeValue:
+		return kind.TimeValue.AsTime()
+	default:
+		return nil
+	}
+}
+
+// toLevel 把core.LoggerLevel转换为Level，从NoticeLevel开始的内置级别数值与Level不同，
+// 需要逐个映射，自定义级别直接使用对应的数值
+func toLevel(level core.LoggerLevel) Level {
+	switch level {
+	case core.TraceLevel:
+		return Level_LEVEL_TRACE
+	case core.DebugLevel:
+		return Level_LEVEL_DEBUG
+	case core.InfoLevel:
+		return Level_LEVEL_INFO
+	case core.NoticeLevel:
+		return Level_LEVEL_NOTICE
+	case core.WarnLevel:
+		return Level_LEVEL_WARN
+	case core.ErrorLevel:
+		return Level_LEVEL_ERROR
+	case core.PanicLevel:
+		return Level_LEVEL_PANIC
+	case core.FatalLevel:
+		return Level_LEVEL_FATAL
+	default:
+		return Level(level)
+	}
+}
+
+// fromLevel 把Level转换为core.LoggerLevel，是toLevel的逆映射
+func fromLevel(level Level) core.LoggerLevel {
+	switch level {
+	case Level_LEVEL_TRACE:
+		return core.TraceLevel
+	case Level_LEVEL_DEBUG:
+		return core.DebugLevel
+	case Level_LEVEL_INFO:
+		return core.InfoLevel
+	case Level_LEVEL_NOTICE:
+		return core.NoticeLevel
+	case Level_LEVEL_WARN:
+		return core.WarnLevel
+	case Level_LEVEL_ERROR:
+		return core.ErrorLevel
+	case Level_LEVEL_PANIC:
+		return core.PanicLevel
+	case Level_LEVEL_FATAL:
+		return core.FatalLevel
+	default:
+		return core.LoggerLevel(level)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 123, time.UTC)
+	e, err := core.NewEntityBuilder().
+		WithTimestamp(now).
+		WithLevel(core.ErrorLevel).
+		WithTraceID("trace-1").
+		WithService("order").
+		WithMessage("create order failed").
+		WithField("user", "alice").
+		WithField("count", 3).
+		WithField("ratio", 0.5).
+		WithField("ok", false).
+		WithField("raw", []byte("abc")).
+		WithField("at", now).
+		WithField("err", errors.New("timeout")).
+		WithField("big", uint64(math.MaxUint64)).
+		WithField("empty", nil).
+		WithCallerEntity(core.NewCallerEntity("order/service.go", 42)).
+		Build()
+	assert.NoError(t, err)
+
+	p := ToProto(e)
+	assert.Equal(t, Level_LEVEL_ERROR, p.GetLevel())
+	assert.Len(t, p.GetFields(), 9)
+	// 结构化字段按照key排序
+	assert.Equal(t, "at", p.GetFields()[0].GetKey())
+
+	b, err := Marshal(e)
+	assert.NoError(t, err)
+	got, err := Unmarshal(b)
+	assert.NoError(t, err)
+	assert.Equal(t, e.Timestamp, got.Timestamp)
+	assert.Equal(t, e.Level, got.Level)
+	assert.Equal(t, e.TraceID, got.TraceID)
+	assert.Equal(t, e.Service, got.Service)
+	assert.Equal(t, e.Message, got.Message)
+	assert.Equal(t, map[string]any{
+		"user":  "alice",
+		"count": int64(3),
+		"ratio": 0.5,
+		"ok":    false,
+		"raw":   []byte("abc"),
+		"at":    now,
+		"err":   "timeout",
+		"big":   "18446744073709551615",
+		"empty": nil,
+	}, got.Fields)
+	assert.Len(t, got.CE, 1)
+	assert.Equal(t, "order/service.go:42", got.CE[0].String())
+
+	_, err = Unmarshal([]byte{0xff})
+	assert.Error(t, err)
+}
+
+func TestConvert_CustomLevel(t *testing.T) {
+	b, err := Marshal(core.Entity{Level: core.LoggerLevel(11), Message: "audit"})
+	assert.NoError(t, err)
+	got, err := Unmarshal(b)
+	assert.NoError(t, err)
+	assert.Equal(t, core.LoggerLevel(11), got.Level)
+	assert.Nil(t, got.Fields)
+	assert.Nil(t, got.CE)
+}
+
+func TestConvert_Level(t *testing.T) {
+	testCases := []struct {
+		level core.LoggerLevel
+		want  Level
+	}{
+		{level: core.TraceLevel, want: Level_LEVEL_TRACE},
+		{level: core.InfoLevel, want: Level_LEVEL_INFO},
+		{level: core.NoticeLevel, want: Level_LEVEL_NOTICE},
+		{level: core.WarnLevel, want: Level_LEVEL_WARN},
+		{level: core.FatalLevel, want: Level_LEVEL_FATAL},
+		{level: core.LoggerLevel(11), want: Level(11)},
+	}
+
+	for _, tc := range testCases {
+		p := ToProto(core.Entity{Level: tc.level})
+		assert.Equal(t, tc.want, p.GetLevel(), tc.level.String())
+		assert.Equal(t, tc.level, FromProto(p).Level, tc.level.String())
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"regexp"
+
+	"github.com/TimeWtr/logx/core"
+)
+
+// RedactionHook 敏感信息脱敏的Hook，使用正则匹配日志内容和字符串类型的字段，
+// 将匹配的内容替换为指定的字符串，比如信用卡号、邮箱、身份证号等
+type RedactionHook struct {
+	// 需要脱敏的正则表达式
+	patterns []*regexp.Regexp
+	// 替换的内容，比如[REDACTED]
+	replacement string
+	// 需要脱敏的日志级别
+	levels []core.LoggerLevel
+}
+
+// NewRedactionHook 创建脱敏Hook，只处理levels指定的日志级别，不指定时处理所有的日志级别，
+// 正则表达式需要预先编译，避免每条日志都重新编译
+func NewRedactionHook(patterns []*regexp.Regexp, replacement string, levels ...core.LoggerLevel) Hook {
+	if len(levels) == 0 {
+		levels = core.AllLevels()
+	}
+
+	return &RedactionHook{
+		patterns:    patterns,
+		replacement: replacement,
+		levels:      levels,
+	}
+}
+
+func (h *RedactionHook) Levels() []core.LoggerLevel {
+	return h.levels
+}
+
+// Fire 直接修改entry的日志内容、原始日志内容和字段
+func (h *RedactionHook) Fire(entry *HookEntry) error {
+	entry.Message = h.redact(entry.Message)
+	entry.RawMessage = h.redact(entry.RawMessage)
+	for i := range entry.Fields {
+		if entry.Fields[i].Type != StringTypeField {
+			continue
+		}
+
+		if val, ok := entry.Fields[i].Value.(string); ok {
+			entry.Fields[i].Value = h.redact(val)
+		}
+	}
+
+	return nil
+}
+
+func (h *RedactionHook) redact(s string) string {
+	for _, p := range h.patterns {
+		s = p.ReplaceAllString(s, h.replacement)
+	}
+
+	return s
+}
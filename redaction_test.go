@@ -0,0 +1,73 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactionHook(t *testing.T) {
+	h := NewRedactionHook([]*regexp.Regexp{
+		regexp.MustCompile(`\b\d{4}[ -]?\d{4}[ -]?\d{4}[ -]?\d{4}\b`),
+		regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`),
+	}, "[REDACTED]")
+	assert.Equal(t, core.AllLevels(), h.Levels())
+
+	entry := &HookEntry{
+		Level:   core.InfoLevel,
+		Message: "user foo@example.com paid with 4111 1111 1111 1111",
+		Fields: []Field{
+			{Key: "email", Type: StringTypeField, Value: "bar@example.com"},
+			{Key: "card", Type: IntTypeField, Value: 4111111111111111},
+		},
+	}
+	assert.NoError(t, h.Fire(entry))
+	assert.Equal(t, "user [REDACTED] paid with [REDACTED]", entry.Message)
+	assert.Equal(t, "[REDACTED]", entry.Fields[0].Value)
+	assert.Equal(t, 4111111111111111, entry.Fields[1].Value)
+
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel}}
+	lg.AddHook(h)
+	lg.AddHook(rh)
+	lg.Info("contact foo@example.com")
+	assert.Len(t, rh.entries, 1)
+	assert.Contains(t, rh.entries[0].Message, "contact [REDACTED]")
+	assert.Equal(t, "contact [REDACTED]", rh.entries[0].RawMessage)
+}
+
+func TestRedactionHook_Levels(t *testing.T) {
+	h := NewRedactionHook([]*regexp.Regexp{regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)}, "[REDACTED]",
+		core.WarnLevel, core.ErrorLevel)
+	assert.Equal(t, []core.LoggerLevel{core.WarnLevel, core.ErrorLevel}, h.Levels())
+
+	lg, err := NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{levels: []core.LoggerLevel{core.InfoLevel, core.WarnLevel}}
+	lg.AddHook(h)
+	lg.AddHook(rh)
+	// 只脱敏指定级别的日志
+	lg.Info("contact foo@example.com")
+	lg.Warn("contact foo@example.com")
+	assert.Len(t, rh.entries, 2)
+	assert.Equal(t, "contact foo@example.com", rh.entries[0].RawMessage)
+	assert.Equal(t, "contact [REDACTED]", rh.entries[1].RawMessage)
+	assert.NoError(t, lg.Close())
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"sync/atomic"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+)
+
+// SamplingHook 按照日志级别采样的Hook，每个级别每N条日志只输出1条，
+// 未命中采样的日志返回errorx.ErrEntrySuppressed丢弃
+type SamplingHook struct {
+	// 每个日志级别的采样率，构造完成后只读
+	rates map[core.LoggerLevel]uint64
+	// 每个日志级别的计数器
+	counters map[core.LoggerLevel]*atomic.Uint64
+	// 需要采样的日志级别
+	levels []core.LoggerLevel
+}
+
+// NewSamplingHook 创建采样Hook，rate为1(或0)表示输出所有日志，100表示每100条输出1条，
+// 通过Logger.AddHook注册，日志文件切换时自动重置计数器
+func NewSamplingHook(rates map[core.LoggerLevel]uint64) *SamplingHook {
+	h := &SamplingHook{
+		rates:    make(map[core.LoggerLevel]uint64, len(rates)),
+		counters: make(map[core.LoggerLevel]*atomic.Uint64, len(rates)),
+		levels:   make([]core.LoggerLevel, 0, len(rates)),
+	}
+	for level, rate := range rates {
+		h.rates[level] = rate
+		h.counters[level] = new(atomic.Uint64)
+		h.levels = append(h.levels, level)
+	}
+
+	return h
+}
+
+func (h *SamplingHook) Levels() []core.LoggerLevel {
+	return h.levels
+}
+
+func (h *SamplingHook) Fire(entry *HookEntry) error {
+	rate := h.rates[entry.Level]
+	counter, ok := h.counters[entry.Level]
+	if !ok || rate <= 1 {
+		return nil
+	}
+
+	// 每个周期的第一条日志输出
+	if (counter.Add(1)-1)%rate == 0 {
+		return nil
+	}
+
+	return errorx.ErrEntrySuppressed
+}
+
+// Reset 重置所有的计数器，日志文件切换时由RotateNow调用，避免计数器单调递增影响采样的判断
+func (h *SamplingHook) Reset() {
+	for _, counter := range h.counters {
+		counter.Store(0)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSamplingHook(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithLevel(core.DebugLevel))
+	assert.NoError(t, err)
+
+	sh := NewSamplingHook(map[core.LoggerLevel]uint64{
+		core.DebugLevel: 10,
+		core.InfoLevel:  1,
+	})
+	rh := &recordHook{levels: []core.LoggerLevel{core.DebugLevel, core.InfoLevel, core.WarnLevel}}
+	lg.AddHook(sh)
+	lg.AddHook(rh)
+
+	for i := 0; i < 100; i++ {
+		lg.Debug("debug message")
+		lg.Info("info message")
+		lg.Warn("warn message")
+	}
+
+	counts := make(map[core.LoggerLevel]int)
+	for _, entry := range rh.entries {
+		counts[entry.Level]++
+	}
+	assert.Equal(t, 10, counts[core.DebugLevel])
+	assert.Equal(t, 100, counts[core.InfoLevel])
+	assert.Equal(t, 100, counts[core.WarnLevel])
+
+	// 重置计数器后，周期的第一条日志立即输出
+	sh.Reset()
+	lg.Debug("debug message")
+	assert.Len(t, rh.entries, 211)
+}
+
+func TestSamplingHook_Rotate(t *testing.T) {
+	lg, err := NewLog(t.TempDir(), WithLevel(core.DebugLevel))
+	assert.NoError(t, err)
+	defer lg.Close()
+
+	sh := NewSamplingHook(map[core.LoggerLevel]uint64{core.DebugLevel: 10})
+	rh := &recordHook{levels: []core.LoggerLevel{core.DebugLevel}}
+	lg.AddHook(sh)
+	lg.AddHook(rh)
+
+	for i := 0; i < 5; i++ {
+		lg.Debug("debug message")
+	}
+	assert.Len(t, rh.entries, 1)
+
+	// 切换日志文件后计数器重新开始，周期的第一条日志立即输出
+	assert.NoError(t, lg.(RotatableLogger).RotateNow())
+	lg.Debug("debug message")
+	assert.Len(t, rh.entries, 2)
+}
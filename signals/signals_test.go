@@ -0,0 +1,51 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package signals
+
+import (
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx"
+	"github.com/stretchr/testify/assert"
+)
+
+// rotateLogger 记录RotateNow调用次数的Logger
+type rotateLogger struct {
+	logx.NopLogger
+	rotates atomic.Int32
+}
+
+func (l *rotateLogger) RotateNow() error {
+	l.rotates.Add(1)
+	return nil
+}
+
+func TestHandleRotateSignal(t *testing.T) {
+	lg := &rotateLogger{}
+	stop := HandleRotateSignal(lg)
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		return lg.rotates.Load() == 1
+	}, time.Second, time.Millisecond*10)
+
+	stop()
+	stop()
+}
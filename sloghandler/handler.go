@@ -0,0 +1,222 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sloghandler 把logx适配为log/slog的Handler，使用slog API的项目也可以复用logx的输出能力
+package sloghandler
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+)
+
+const (
+	// LevelTrace slog中没有对应的级别，映射为core.TraceLevel
+	LevelTrace = slog.LevelDebug - 4
+	// LevelNotice slog中没有对应的级别，映射为core.NoticeLevel
+	LevelNotice = slog.LevelInfo + 2
+	// LevelPanic slog中没有对应的级别，映射为core.PanicLevel
+	LevelPanic = slog.LevelError + 4
+	// LevelFatal slog中没有对应的级别，映射为core.FatalLevel
+	LevelFatal = slog.LevelError + 8
+)
+
+// SourceKey 开启WithAddSource时调用方位置的字段名
+const SourceKey = slog.SourceKey
+
+// Options Handler的配置选项
+type Options func(*Handler)
+
+// WithAddSource 对应slog.HandlerOptions.AddSource，输出调用方位置，格式为"file:line"，
+// 文件路径与logx的调用方信息一致只保留core.DefaultParts层
+func WithAddSource() Options {
+	return func(h *Handler) {
+		h.addSource = true
+	}
+}
+
+// Handler 实现slog.Handler接口，把slog.Record的字段转换为键值对后调用logx.Logger对应级别的
+// 结构化方法写入，logx.Logger实现了logx.TimedLogger时保留slog.Record的时间
+type Handler struct {
+	lg logx.Logger
+	// WithAttrs添加的字段，key已经带有分组前缀
+	attrs []slog.Attr
+	// WithGroup添加的分组前缀，格式为"group1.group2."
+	prefix string
+	// 是否输出调用方位置
+	addSource bool
+}
+
+func NewSlogHandler(lg logx.Logger, opts ...Options) slog.Handler {
+	h := &Handler{lg: lg}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Enabled 按照logx当前的日志级别判断是否需要输出
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.lg.GetLevel().Enabled(toLevel(level))
+}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	level := toLevel(r.Level)
+	kvs := h.keysAndValues(r)
+	if tl, ok := h.lg.(logx.TimedLogger); ok {
+		tl.Logw(r.Time, level, r.Message, kvs...)
+		return nil
+	}
+
+	switch level {
+	case core.TraceLevel:
+		h.lg.Tracew(r.Message, kvs...)
+	case core.DebugLevel:
+		h.lg.Debugw(r.Message, kvs...)
+	case core.InfoLevel:
+		h.lg.Infow(r.Message, kvs...)
+	case core.NoticeLevel:
+		h.lg.Noticew(r.Message, kvs...)
+	case core.WarnLevel:
+		h.lg.Warnw(r.Message, kvs...)
+	case core.ErrorLevel:
+		h.lg.Errorw(r.Message, kvs...)
+	case core.PanicLevel:
+		h.lg.Panicw(r.Message, kvs...)
+	case core.FatalLevel:
+		h.lg.Fatalw(r.Message, kvs...)
+	default:
+	}
+
+	return nil
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	nh := h.clone()
+	for _, a := range attrs {
+		nh.attrs = appendAttr(nh.attrs, h.prefix, a)
+	}
+
+	return nh
+}
+
+// WithGroup 之后添加的字段都带有name前缀，name为空时返回原Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	nh := h.clone()
+	nh.prefix = h.prefix + name + "."
+	return nh
+}
+
+func (h *Handler) clone() *Handler {
+	return &Handler{
+		lg:        h.lg,
+		attrs:     append([]slog.Attr(nil), h.attrs...),
+		prefix:    h.prefix,
+		addSource: h.addSource,
+	}
+}
+
+// keysAndValues 按照WithAttrs、Record的顺序把字段转换为交替出现的key、value，
+// 开启AddSource时最后追加调用方位置
+func (h *Handler) keysAndValues(r slog.Record) []any {
+	// Clip之后追加时重新分配，并发的Handle不会写入共享的底层数组
+	attrs := slices.Clip(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttr(attrs, h.prefix, a)
+		return true
+	})
+
+	kvs := make([]any, 0, 2*len(attrs)+2)
+	for _, a := range attrs {
+		kvs = append(kvs, a.Key, a.Value.Any())
+	}
+	// slog.Logger总是会记录PC，只有开启AddSource时才输出
+	if h.addSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		kvs = append(kvs, SourceKey, source(frame))
+	}
+
+	return kvs
+}
+
+// source 把调用方位置格式化为"file:line"，文件路径只保留最后core.DefaultParts层
+func source(frame runtime.Frame) string {
+	file := frame.File
+	// runtime返回的路径总是使用"/"分隔
+	sli := strings.Split(file, "/")
+	if len(sli) > core.DefaultParts {
+		file = strings.Join(sli[len(sli)-core.DefaultParts:], "/")
+	}
+
+	return file + ":" + strconv.Itoa(frame.Line)
+}
+
+// appendAttr 解析字段的值并加上分组前缀，分组类型的字段展开为多个字段，空字段被忽略
+func appendAttr(attrs []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		// 匿名分组的字段直接内联
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			attrs = appendAttr(attrs, prefix, ga)
+		}
+		return attrs
+	}
+
+	a.Key = prefix + a.Key
+	return append(attrs, a)
+}
+
+// toLevel 把slog的日志级别映射为logx的日志级别，介于两个级别之间的按照较低的级别处理
+func toLevel(level slog.Level) core.LoggerLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return core.TraceLevel
+	case level < slog.LevelInfo:
+		return core.DebugLevel
+	case level < LevelNotice:
+		return core.InfoLevel
+	case level < slog.LevelWarn:
+		return core.NoticeLevel
+	case level < slog.LevelError:
+		return core.WarnLevel
+	case level < LevelPanic:
+		return core.ErrorLevel
+	case level < LevelFatal:
+		return core.PanicLevel
+	default:
+		return core.FatalLevel
+	}
+}
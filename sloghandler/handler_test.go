@@ -0,0 +1,129 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sloghandler
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx"
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordHook struct {
+	entries []logx.HookEntry
+}
+
+func (h *recordHook) Levels() []core.LoggerLevel {
+	return core.AllLevels()
+}
+
+func (h *recordHook) Fire(entry *logx.HookEntry) error {
+	h.entries = append(h.entries, *entry)
+	return nil
+}
+
+func TestToLevel(t *testing.T) {
+	testCases := []struct {
+		level slog.Level
+		want  core.LoggerLevel
+	}{
+		{level: LevelTrace, want: core.TraceLevel},
+		{level: slog.LevelDebug - 1, want: core.TraceLevel},
+		{level: slog.LevelDebug, want: core.DebugLevel},
+		{level: slog.LevelInfo, want: core.InfoLevel},
+		{level: slog.LevelInfo + 1, want: core.InfoLevel},
+		{level: LevelNotice, want: core.NoticeLevel},
+		{level: slog.LevelWarn - 1, want: core.NoticeLevel},
+		{level: slog.LevelWarn, want: core.WarnLevel},
+		{level: slog.LevelError, want: core.ErrorLevel},
+		{level: LevelPanic, want: core.PanicLevel},
+		{level: LevelFatal, want: core.FatalLevel},
+		{level: LevelFatal + 4, want: core.FatalLevel},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, toLevel(tc.level), tc.level.String())
+	}
+}
+
+func TestHandler(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir(), logx.WithLevel(core.InfoLevel))
+	assert.NoError(t, err)
+	rh := &recordHook{}
+	lg.AddHook(rh)
+
+	h := NewSlogHandler(lg)
+	assert.False(t, h.Enabled(context.Background(), slog.LevelDebug))
+	assert.True(t, h.Enabled(context.Background(), slog.LevelInfo))
+
+	sl := slog.New(h).With("service", "api").WithGroup("req")
+	sl.Debug("ignored")
+	sl.Info("hello world", "id", 1, slog.Group("user", "name", "tom cat"), slog.Attr{})
+	sl.Error("failed", "err", "timeout")
+	sl.Log(context.Background(), LevelFatal, "fatal")
+
+	assert.Len(t, rh.entries, 3)
+	assert.Equal(t, core.InfoLevel, rh.entries[0].Level)
+	assert.Contains(t, rh.entries[0].Message, `hello world service=api req.id=1 req.user.name="tom cat"`)
+	assert.Equal(t, "hello world", rh.entries[0].RawMessage)
+	assert.Equal(t, []logx.Field{
+		logx.StringField("service", "api"),
+		logx.IntField("req.id", 1),
+		logx.StringField("req.user.name", "tom cat"),
+	}, rh.entries[0].Fields[:3])
+	assert.Equal(t, core.ErrorLevel, rh.entries[1].Level)
+	assert.Contains(t, rh.entries[1].Message, "failed service=api req.err=timeout")
+	assert.Equal(t, core.FatalLevel, rh.entries[2].Level)
+}
+
+func TestHandler_Time(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir(), logx.WithUTC())
+	assert.NoError(t, err)
+	rh := &recordHook{}
+	lg.AddHook(rh)
+
+	// 使用slog.Record的时间，而不是写入的时间
+	ts := time.Date(2025, 5, 12, 12, 12, 0, 0, time.UTC)
+	r := slog.NewRecord(ts, slog.LevelInfo, "replayed", 0)
+	assert.NoError(t, NewSlogHandler(lg).Handle(context.Background(), r))
+	assert.Len(t, rh.entries, 1)
+	assert.True(t, ts.Equal(rh.entries[0].Time))
+	assert.Contains(t, rh.entries[0].Message, "2025-05-12")
+}
+
+func TestHandler_Source(t *testing.T) {
+	lg, err := logx.NewLog(t.TempDir())
+	assert.NoError(t, err)
+	rh := &recordHook{}
+	lg.AddHook(rh)
+
+	// slog.Logger总是会记录PC，未开启AddSource时不输出调用方位置
+	slog.New(NewSlogHandler(lg)).Info("without source")
+	// WithAttrs派生的Handler保留AddSource
+	slog.New(NewSlogHandler(lg, WithAddSource())).With("k", "v").Info("with source")
+	assert.Len(t, rh.entries, 2)
+	assert.NotContains(t, rh.entries[0].Message, SourceKey+"=")
+	assert.Contains(t, rh.entries[1].Message, SourceKey+"=")
+	assert.Contains(t, rh.entries[1].Message, "sloghandler/handler_test.go:")
+
+	// 文件路径只保留最后core.DefaultParts层
+	assert.Equal(t, "c/d/e/f.go:3", source(runtime.Frame{File: "/a/b/c/d/e/f.go", Line: 3}))
+	assert.Equal(t, "e/f.go:3", source(runtime.Frame{File: "e/f.go", Line: 3}))
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/TimeWtr/logx/core"
+)
+
+// testCallerSkip TestLogger获取调用方堆栈信息需要跳过的层级
+// 调用链：caller -> OrignalEntity -> capture -> Info等 -> 调用方
+const testCallerSkip = 4
+
+// CapturedEntry TestLogger捕获的日志数据
+type CapturedEntry struct {
+	// 日志级别
+	Level core.LoggerLevel
+	// 日志内容，不包含时间和级别前缀
+	Message string
+	// 结构化信息
+	Fields []Field
+	// 调用方的堆栈信息
+	CallerInfo core.CallerEntity
+}
+
+// testRecorder TestLogger父子实例共享的捕获结果和日志级别
+type testRecorder struct {
+	// 并发保护
+	mu sync.Mutex
+	// 捕获的日志
+	entries []CapturedEntry
+	// 当前的日志级别，默认为TraceLevel，捕获所有级别的日志
+	level atomic.Uint32
+}
+
+// TestLogger 捕获所有日志的Logger，用于在单元测试中断言日志的输出，并发安全
+type TestLogger struct {
+	*testRecorder
+	// 获取调用方的堆栈信息
+	caller *core.CallEntityWrap
+	// 子实例携带的字段，每条捕获的日志都会携带
+	fields []Field
+}
+
+func NewTestLogger() *TestLogger {
+	t := &TestLogger{
+		testRecorder: &testRecorder{},
+		caller:       core.NewCallEntityWrap(core.WithSkip(testCallerSkip)),
+	}
+	t.level.Store(uint32(core.TraceLevel))
+
+	return t
+}
+
+func (t *TestLogger) Trace(v ...any) {
+	t.capture(core.TraceLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Debug(v ...any) {
+	t.capture(core.DebugLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Info(v ...any) {
+	t.capture(core.InfoLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Notice(v ...any) {
+	t.capture(core.NoticeLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Warn(v ...any) {
+	t.capture(core.WarnLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Error(v ...any) {
+	t.capture(core.ErrorLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Panic(v ...any) {
+	t.capture(core.PanicLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Fatal(v ...any) {
+	t.capture(core.FatalLevel, fmt.Sprint(v...), nil)
+}
+
+func (t *TestLogger) Tracef(format string, v ...any) {
+	t.capture(core.TraceLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Debugf(format string, v ...any) {
+	t.capture(core.DebugLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Infof(format string, v ...any) {
+	t.capture(core.InfoLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Noticef(format string, v ...any) {
+	t.capture(core.NoticeLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Warnf(format string, v ...any) {
+	t.capture(core.WarnLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Errorf(format string, v ...any) {
+	t.capture(core.ErrorLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Panicf(format string, v ...any) {
+	t.capture(core.PanicLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Fatalf(format string, v ...any) {
+	t.capture(core.FatalLevel, fmt.Sprintf(format, v...), nil)
+}
+
+func (t *TestLogger) Tracew(msg string, keysAndValues ...any) {
+	t.capture(core.TraceLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Debugw(msg string, keysAndValues ...any) {
+	t.capture(core.DebugLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Infow(msg string, keysAndValues ...any) {
+	t.capture(core.InfoLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Noticew(msg string, keysAndValues ...any) {
+	t.capture(core.NoticeLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Warnw(msg string, keysAndValues ...any) {
+	t.capture(core.WarnLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Errorw(msg string, keysAndValues ...any) {
+	t.capture(core.ErrorLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Panicw(msg string, keysAndValues ...any) {
+	t.capture(core.PanicLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) Fatalw(msg string, keysAndValues ...any) {
+	t.capture(core.FatalLevel, msg, sweetenFields(keysAndValues))
+}
+
+func (t *TestLogger) SetLevel(level core.LoggerLevel) {
+	if !level.Valid() {
+		return
+	}
+
+	t.level.Store(uint32(level))
+}
+
+func (t *TestLogger) GetLevel() core.LoggerLevel {
+	return core.LoggerLevel(t.level.Load())
+}
+
+// AddHook 日志只会被捕获，Hook不会被触发
+func (t *TestLogger) AddHook(_ Hook) {}
+
+func (t *TestLogger) RemoveHook(_ Hook) {}
+
+func (t *TestLogger) Flush() error {
+	return nil
+}
+
+// SetWriter 日志只会被捕获，写入器不会被添加
+func (t *TestLogger) SetWriter(_ string, _ core.Writer) error {
+	return nil
+}
+
+func (t *TestLogger) RemoveWriter(_ string) {}
+
+func (t *TestLogger) Writers() []string {
+	return nil
+}
+
+// WithError 返回携带error字段的子实例，捕获的日志与父实例共享，err为nil时返回自身
+func (t *TestLogger) WithError(err error) Logger {
+	if err == nil {
+		return t
+	}
+
+	child := *t
+	child.fields = slices.Concat(t.fields, errorFields(err))
+
+	return &child
+}
+
+func (t *TestLogger) Discard() Logger {
+	return NewNopLogger()
+}
+
+func (t *TestLogger) Close() error {
+	return nil
+}
+
+// Entries 返回捕获的所有日志的副本
+func (t *TestLogger) Entries() []CapturedEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]CapturedEntry(nil), t.entries...)
+}
+
+// EntriesForLevel 返回捕获的指定级别的日志
+func (t *TestLogger) EntriesForLevel(level core.LoggerLevel) []CapturedEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var res []CapturedEntry
+	for _, entry := range t.entries {
+		if entry.Level == level {
+			res = append(res, entry)
+		}
+	}
+
+	return res
+}
+
+// Contains 是否捕获了指定级别并且内容包含substr的日志
+func (t *TestLogger) Contains(level core.LoggerLevel, substr string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, entry := range t.entries {
+		if entry.Level == level && strings.Contains(entry.Message, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reset 清空捕获的日志
+func (t *TestLogger) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries = nil
+}
+
+func (t *TestLogger) capture(level core.LoggerLevel, msg string, fields []Field) {
+	if !t.GetLevel().Enabled(level) {
+		return
+	}
+
+	entry := CapturedEntry{
+		Level:      level,
+		Message:    msg,
+		Fields:     slices.Concat(t.fields, fields),
+		CallerInfo: t.caller.OrignalEntity(),
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = append(t.entries, entry)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestLogger(t *testing.T) {
+	var lg Logger = NewTestLogger()
+	tl, _ := lg.(*TestLogger)
+
+	lg.Debug("debug message")
+	lg.Infof("info %s", "message")
+	lg.Errorf("error %d", 1)
+	lg.Error("error ", 2)
+
+	assert.Len(t, tl.Entries(), 4)
+	assert.Len(t, tl.EntriesForLevel(core.ErrorLevel), 2)
+	assert.True(t, tl.Contains(core.InfoLevel, "info message"))
+	assert.True(t, tl.Contains(core.ErrorLevel, "error 2"))
+	assert.False(t, tl.Contains(core.WarnLevel, "info message"))
+	assert.Equal(t, "testlogger_test.go", filepath.Base(tl.Entries()[0].CallerInfo.File()))
+
+	tl.Reset()
+	assert.Empty(t, tl.Entries())
+
+	lg.Infow("info message", "user", "tom")
+	assert.Equal(t, []Field{{Key: "user", Type: StringTypeField, Value: "tom"}}, tl.Entries()[0].Fields)
+	assert.Equal(t, "testlogger_test.go", filepath.Base(tl.Entries()[0].CallerInfo.File()))
+	tl.Reset()
+
+	// 低于当前日志级别的日志不会被捕获
+	lg.SetLevel(core.WarnLevel)
+	lg.Info("info message")
+	lg.Warn("warn message")
+	assert.Len(t, tl.Entries(), 1)
+	tl.Reset()
+
+	lg.SetLevel(core.NoticeLevel)
+	lg.Info("info message")
+	lg.Noticef("notice %s", "message")
+	assert.True(t, tl.Contains(core.NoticeLevel, "notice message"))
+	assert.Len(t, tl.Entries(), 1)
+	tl.Reset()
+
+	// 子实例与父实例共享捕获的日志
+	child := lg.WithError(errors.New("mock error"))
+	child.Warnw("warn message", "user", "tom")
+	assert.Equal(t, []Field{
+		{Key: "error", Type: StringTypeField, Value: "mock error"},
+		{Key: "user", Type: StringTypeField, Value: "tom"},
+	}, tl.Entries()[0].Fields)
+	assert.Equal(t, "testlogger_test.go", filepath.Base(tl.Entries()[0].CallerInfo.File()))
+	assert.Same(t, lg, lg.WithError(nil))
+}
+
+func TestTestLogger_Concurrent(t *testing.T) {
+	tl := NewTestLogger()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				tl.Infof("message %d", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, tl.Entries(), 1000)
+}
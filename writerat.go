@@ -0,0 +1,75 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/TimeWtr/logx/core"
+)
+
+// WriterAt 把io.WriterAt的写入转换为指定级别的日志，用于只接受io.WriterAt的库，
+// 日志是顺序写入的，所以忽略偏移量，并发安全
+type WriterAt struct {
+	// 写入的目标Logger
+	lg Logger
+	// 日志级别
+	level core.LoggerLevel
+	// 通过WriteAt写入的总字节数
+	size atomic.Int64
+}
+
+// NewIOWriterAt 创建写入指定级别日志的WriterAt，不是内置的日志级别时使用InfoLevel
+func NewIOWriterAt(lg Logger, level core.LoggerLevel) *WriterAt {
+	if level < core.TraceLevel || level > core.FatalLevel {
+		level = core.InfoLevel
+	}
+
+	return &WriterAt{lg: lg, level: level}
+}
+
+// WriteAt 忽略off，去除末尾的换行符后作为一条日志写入，Logger没有读写位置，
+// 满足io.WriterAt不影响底层写入位置的约定
+func (w *WriterAt) WriteAt(p []byte, _ int64) (n int, err error) {
+	msg := strings.TrimRight(string(p), "\r\n")
+	switch w.level {
+	case core.TraceLevel:
+		w.lg.Trace(msg)
+	case core.DebugLevel:
+		w.lg.Debug(msg)
+	case core.InfoLevel:
+		w.lg.Info(msg)
+	case core.NoticeLevel:
+		w.lg.Notice(msg)
+	case core.WarnLevel:
+		w.lg.Warn(msg)
+	case core.ErrorLevel:
+		w.lg.Error(msg)
+	case core.PanicLevel:
+		w.lg.Panic(msg)
+	case core.FatalLevel:
+		w.lg.Fatal(msg)
+	default:
+	}
+	w.size.Add(int64(len(p)))
+
+	return len(p), nil
+}
+
+// Size 返回通过WriteAt写入的总字节数，包括被去除的换行符
+func (w *WriterAt) Size() int64 {
+	return w.size.Load()
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logx
+
+import (
+	"io"
+	"testing"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterAt(t *testing.T) {
+	tl := NewTestLogger()
+	var w io.WriterAt = NewIOWriterAt(tl, core.WarnLevel)
+
+	n, err := w.WriteAt([]byte("first part\n"), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, n)
+	n, err = w.WriteAt([]byte("second part\r\n"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 13, n)
+	assert.Equal(t, int64(24), w.(*WriterAt).Size())
+
+	entries := tl.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, core.WarnLevel, entries[0].Level)
+	assert.Equal(t, "first part", entries[0].Message)
+	assert.Equal(t, "second part", entries[1].Message)
+
+	// 不是内置的日志级别时使用InfoLevel
+	_, err = NewIOWriterAt(tl, core.LoggerLevel(100)).WriteAt([]byte("info"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, core.InfoLevel, tl.Entries()[2].Level)
+
+	_, err = NewIOWriterAt(tl, core.NoticeLevel).WriteAt([]byte("notice"), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, core.NoticeLevel, tl.Entries()[3].Level)
+}
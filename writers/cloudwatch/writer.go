@@ -0,0 +1,237 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cloudwatch 把日志批量发送到AWS CloudWatch Logs的写入器，独立的模块避免core用户引入AWS SDK依赖
+package cloudwatch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+)
+
+const (
+	// MaxBatchEvents 单次PutLogEvents请求允许的最大日志条数
+	MaxBatchEvents = 10000
+	// MaxBatchSize 单次PutLogEvents请求允许的最大字节数
+	MaxBatchSize = 1048576
+	// eventOverhead AWS计算批次大小时每条日志额外计算的字节数
+	eventOverhead = 26
+	// DefaultBatchInterval 默认的自动刷新间隔
+	DefaultBatchInterval = 5 * time.Second
+)
+
+type CWOption func(w *Writer)
+
+// WithCWBatchInterval 设置自动刷新的间隔，小于等于0时关闭自动刷新，只在批次满、Flush和Close时发送
+func WithCWBatchInterval(d time.Duration) CWOption {
+	return func(w *Writer) {
+		w.interval = d
+	}
+}
+
+// Writer CloudWatch Logs写入器，日志先缓存在批次中，达到AWS的限制(10000条或者1MB)、
+// 定时或者调用Flush时通过PutLogEvents发送
+type Writer struct {
+	// CloudWatch Logs客户端
+	client cloudwatchlogsiface.CloudWatchLogsAPI
+	// 日志组
+	logGroup string
+	// 日志流
+	logStream string
+	// 自动刷新的间隔
+	interval time.Duration
+	// 并发保护，同时保证批次按照顺序发送
+	lock sync.Mutex
+	// 当前的批次
+	events []*cloudwatchlogs.InputLogEvent
+	// 当前批次的字节数，包含每条日志的额外字节数
+	size int
+	// 下一次请求使用的序列号
+	token *string
+	// 关闭信号
+	sig chan struct{}
+	// 单例
+	once sync.Once
+	// 等待自动刷新的goroutine退出
+	wg sync.WaitGroup
+}
+
+func NewCloudWatchWriter(logGroup, logStream string,
+	client cloudwatchlogsiface.CloudWatchLogsAPI, opts ...CWOption) (core.Writer, error) {
+	if logGroup == "" || logStream == "" {
+		return nil, errors.New("log group and log stream can't be empty")
+	}
+	if client == nil {
+		return nil, errors.New("cloudwatch logs client can't be nil")
+	}
+
+	w := &Writer{
+		client:    client,
+		logGroup:  logGroup,
+		logStream: logStream,
+		interval:  DefaultBatchInterval,
+		sig:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.interval > 0 {
+		w.wg.Add(1)
+		go w.asyncFlush()
+	}
+
+	return w, nil
+}
+
+// Write 每次写入的数据作为一条日志，时间为写入的时间
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if err = w.add(string(p), time.Now().UnixMilli()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteEntity 结构化日志序列化为JSON，方便CloudWatch Logs Insights查询，时间为日志的时间
+func (w *Writer) WriteEntity(e core.Entity) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return w.add(string(data), time.Unix(0, e.Timestamp).UnixMilli())
+}
+
+// Flush 同步发送当前的批次
+func (w *Writer) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.flush()
+}
+
+// Close 停止自动刷新，并发送剩余的日志
+func (w *Writer) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.sig)
+		w.wg.Wait()
+		err = w.Flush()
+	})
+
+	return err
+}
+
+func (w *Writer) add(msg string, ts int64) error {
+	size := len(msg) + eventOverhead
+	if size > MaxBatchSize {
+		return fmt.Errorf("%w: %d bytes over cloudwatch limit", errorx.ErrMessageTooLarge, size)
+	}
+
+	select {
+	case <-w.sig:
+		return errorx.ErrBufferClose
+	default:
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// 加入后超过AWS的限制时先发送当前的批次
+	if len(w.events)+1 > MaxBatchEvents || w.size+size > MaxBatchSize {
+		if err := w.flush(); err != nil {
+			return err
+		}
+	}
+
+	w.events = append(w.events, &cloudwatchlogs.InputLogEvent{
+		Message:   aws.String(msg),
+		Timestamp: aws.Int64(ts),
+	})
+	w.size += size
+	return nil
+}
+
+// flush 发送当前的批次，序列号失效时使用返回的序列号重试一次，调用方需要持有锁
+func (w *Writer) flush() error {
+	if len(w.events) == 0 {
+		return nil
+	}
+
+	err := w.put()
+	var ite *cloudwatchlogs.InvalidSequenceTokenException
+	if errors.As(err, &ite) {
+		w.token = ite.ExpectedSequenceToken
+		err = w.put()
+	}
+
+	// 批次已经被接收过，不需要重复发送
+	var dae *cloudwatchlogs.DataAlreadyAcceptedException
+	if errors.As(err, &dae) {
+		w.token = dae.ExpectedSequenceToken
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	w.events = w.events[:0]
+	w.size = 0
+	return nil
+}
+
+func (w *Writer) put() error {
+	out, err := w.client.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(w.logGroup),
+		LogStreamName: aws.String(w.logStream),
+		LogEvents:     w.events,
+		SequenceToken: w.token,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.token = out.NextSequenceToken
+	return nil
+}
+
+// asyncFlush 定时发送批次，失败的批次保留到下一次发送，错误输出到标准错误
+func (w *Writer) asyncFlush() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.sig:
+			return
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "logx: flush cloudwatch logs failed: %s\n", err)
+			}
+		}
+	}
+}
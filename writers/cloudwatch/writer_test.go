@@ -0,0 +1,107 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudwatch
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+	lock sync.Mutex
+	// 每次请求的日志条数
+	batches []int
+	// 每次请求使用的序列号
+	tokens []string
+	// 第一次请求返回序列号失效
+	invalidToken bool
+}
+
+func (m *mockClient) PutLogEvents(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.tokens = append(m.tokens, aws.StringValue(input.SequenceToken))
+	if m.invalidToken {
+		m.invalidToken = false
+		return nil, &cloudwatchlogs.InvalidSequenceTokenException{ExpectedSequenceToken: aws.String("expected")}
+	}
+
+	m.batches = append(m.batches, len(input.LogEvents))
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String("next")}, nil
+}
+
+func (m *mockClient) sent() []int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]int(nil), m.batches...)
+}
+
+func TestNewCloudWatchWriter(t *testing.T) {
+	_, err := NewCloudWatchWriter("", "stream", &mockClient{})
+	assert.Error(t, err)
+	_, err = NewCloudWatchWriter("group", "stream", nil)
+	assert.Error(t, err)
+}
+
+func TestWriter_Batch(t *testing.T) {
+	client := &mockClient{invalidToken: true}
+	w, err := NewCloudWatchWriter("group", "stream", client, WithCWBatchInterval(0))
+	assert.NoError(t, err)
+
+	for i := 0; i < MaxBatchEvents+1; i++ {
+		_, err = w.Write([]byte("hello"))
+		assert.NoError(t, err)
+	}
+	// 达到条数限制时自动发送，序列号失效时使用返回的序列号重试
+	assert.Equal(t, []int{MaxBatchEvents}, client.sent())
+	assert.Equal(t, []string{"", "expected"}, client.tokens)
+
+	assert.NoError(t, w.WriteEntity(core.Entity{Level: core.ErrorLevel, Message: "entity"}))
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, []int{MaxBatchEvents, 2}, client.sent())
+	assert.Equal(t, "next", client.tokens[2])
+
+	_, err = w.Write([]byte(strings.Repeat("a", MaxBatchSize)))
+	assert.ErrorIs(t, err, errorx.ErrMessageTooLarge)
+
+	assert.NoError(t, w.Close())
+	_, err = w.Write([]byte("hello"))
+	assert.ErrorIs(t, err, errorx.ErrBufferClose)
+}
+
+func TestWriter_Interval(t *testing.T) {
+	client := &mockClient{}
+	w, err := NewCloudWatchWriter("group", "stream", client, WithCWBatchInterval(10*time.Millisecond))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return len(client.sent()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
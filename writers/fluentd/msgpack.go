@@ -0,0 +1,224 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentd
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// eventTimeExt Fluentd EventTime的MessagePack扩展类型
+const eventTimeExt = 0
+
+// appendArrayHeader 写入数组的长度
+func appendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xdc), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, 0xdd), uint32(n))
+	}
+}
+
+// appendMapHeader 写入map的键值对数量
+func appendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+	}
+}
+
+func appendString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+
+	return append(b, s...)
+}
+
+func appendBytes(b, p []byte) []byte {
+	n := len(p)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+
+	return append(b, p...)
+}
+
+func appendInt(b []byte, v int64) []byte {
+	if v >= 0 {
+		return appendUint(b, uint64(v))
+	}
+	if v >= -32 {
+		return append(b, byte(v))
+	}
+
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+}
+
+func appendUint(b []byte, v uint64) []byte {
+	if v < 128 {
+		return append(b, byte(v))
+	}
+
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+}
+
+func appendFloat(b []byte, v float64) []byte {
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v))
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+
+	return append(b, 0xc2)
+}
+
+// appendEventTime 使用fixext8编码EventTime，秒和纳秒各4字节，精度比整数秒更高
+func appendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, eventTimeExt)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+// appendValue 按照值的类型编码，无法直接表示的类型使用字符串格式
+func appendValue(b []byte, v any) []byte {
+	switch val := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case string:
+		return appendString(b, val)
+	case []byte:
+		return appendBytes(b, val)
+	case bool:
+		return appendBool(b, val)
+	case int:
+		return appendInt(b, int64(val))
+	case int8:
+		return appendInt(b, int64(val))
+	case int16:
+		return appendInt(b, int64(val))
+	case int32:
+		return appendInt(b, int64(val))
+	case int64:
+		return appendInt(b, val)
+	case uint:
+		return appendUint(b, uint64(val))
+	case uint8:
+		return appendUint(b, uint64(val))
+	case uint16:
+		return appendUint(b, uint64(val))
+	case uint32:
+		return appendUint(b, uint64(val))
+	case uint64:
+		return appendUint(b, val)
+	case float32:
+		return appendFloat(b, float64(val))
+	case float64:
+		return appendFloat(b, val)
+	case time.Time:
+		return appendString(b, val.Format(time.RFC3339Nano))
+	case error:
+		return appendString(b, val.Error())
+	case fmt.Stringer:
+		return appendString(b, val.String())
+	default:
+		return appendString(b, fmt.Sprint(val))
+	}
+}
+
+// errUnexpectedAck 服务端返回的确认消息不是{"ack": chunk}格式
+var errUnexpectedAck = errors.New("unexpected fluentd ack response")
+
+// readAck 读取服务端的确认消息{"ack": chunk}，返回chunk
+func readAck(r io.Reader) (string, error) {
+	var header [1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", err
+	}
+	if header[0] != 0x81 {
+		return "", errUnexpectedAck
+	}
+
+	key, err := readString(r)
+	if err != nil {
+		return "", err
+	}
+	if key != "ack" {
+		return "", errUnexpectedAck
+	}
+
+	return readString(r)
+}
+
+// readString 读取fixstr、str8和str16格式的字符串
+func readString(r io.Reader) (string, error) {
+	var header [1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", err
+	}
+
+	var n int
+	switch {
+	case header[0]&0xe0 == 0xa0:
+		n = int(header[0] & 0x1f)
+	case header[0] == 0xd9:
+		var size [1]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return "", err
+		}
+		n = int(size[0])
+	case header[0] == 0xda:
+		var size [2]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			return "", err
+		}
+		n = int(binary.BigEndian.Uint16(size[:]))
+	default:
+		return "", errUnexpectedAck
+	}
+
+	s := make([]byte, n)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+
+	return string(s), nil
+}
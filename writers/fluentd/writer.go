@@ -0,0 +1,351 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fluentd 通过Fluentd forward协议批量发送日志的写入器，日志编码为MessagePack格式
+package fluentd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+)
+
+const (
+	// DefaultBufferSize 默认的批次大小，达到后立即发送
+	DefaultBufferSize = 100
+	// DefaultFlushInterval 默认的自动发送间隔
+	DefaultFlushInterval = time.Second
+	// DefaultTimeout 默认的连接、发送和等待确认的超时时间
+	DefaultTimeout = 3 * time.Second
+	// MessageKey Write写入的数据在记录中的字段名称
+	MessageKey = "message"
+	// chunkSize 批次ID的随机字节数
+	chunkSize = 16
+)
+
+type FluentdOption func(w *Writer)
+
+// WithFluentdBufferSize 设置批次的大小，批次中的日志条数达到后立即发送，小于等于1时每条日志单独发送
+func WithFluentdBufferSize(size int) FluentdOption {
+	return func(w *Writer) {
+		w.bufferSize = size
+	}
+}
+
+// WithFluentdFlushInterval 设置自动发送的间隔，小于等于0时关闭自动发送
+func WithFluentdFlushInterval(d time.Duration) FluentdOption {
+	return func(w *Writer) {
+		w.interval = d
+	}
+}
+
+// WithFluentdTimeout 设置连接、发送和等待确认的超时时间
+func WithFluentdTimeout(d time.Duration) FluentdOption {
+	return func(w *Writer) {
+		w.timeout = d
+	}
+}
+
+// WithFluentdRequireAck 开启at-least-once语义，每个批次携带chunk并等待服务端返回确认
+func WithFluentdRequireAck() FluentdOption {
+	return func(w *Writer) {
+		w.requireAck = true
+	}
+}
+
+// Writer Fluentd写入器，日志先缓存在批次中，达到批次大小、定时或者调用Flush时以forward模式
+// [tag, [[time, record], ...], option]发送，发送失败时重新建立连接并重试一次
+type Writer struct {
+	// Fluentd的TCP地址
+	addr string
+	// 日志的tag
+	tag string
+	// 批次的大小
+	bufferSize int
+	// 自动发送的间隔
+	interval time.Duration
+	// 连接、发送和等待确认的超时时间
+	timeout time.Duration
+	// 是否等待服务端确认
+	requireAck bool
+	// 并发保护，同时保证批次按照顺序发送
+	lock sync.Mutex
+	// 网络连接，发送失败后置空，下次发送时重新建立
+	conn net.Conn
+	// 当前的批次，每一项是编码后的[time, record]
+	entries [][]byte
+	// 关闭信号
+	sig chan struct{}
+	// 单例
+	once sync.Once
+	// 等待自动发送的goroutine退出
+	wg sync.WaitGroup
+}
+
+// NewFluentdWriter 创建Fluentd写入器并建立连接，addr为Fluentd forward输入的地址，比如127.0.0.1:24224
+func NewFluentdWriter(addr, tag string, opts ...FluentdOption) (core.Writer, error) {
+	if tag == "" {
+		return nil, errors.New("fluentd tag can't be empty")
+	}
+
+	w := &Writer{
+		addr:       addr,
+		tag:        tag,
+		bufferSize: DefaultBufferSize,
+		interval:   DefaultFlushInterval,
+		timeout:    DefaultTimeout,
+		sig:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.bufferSize < 1 {
+		w.bufferSize = 1
+	}
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	if w.interval > 0 {
+		w.wg.Add(1)
+		go w.asyncFlush()
+	}
+
+	return w, nil
+}
+
+// Write 每次写入的数据作为一条日志，记录为{"message": 数据}，时间为写入的时间
+func (w *Writer) Write(p []byte) (n int, err error) {
+	record := appendMapHeader(nil, 1)
+	record = appendString(record, MessageKey)
+	record = appendString(record, strings.TrimSuffix(string(p), "\n"))
+	if err = w.add(time.Now(), record); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteEntity 结构化日志的字段直接作为记录的字段，level、message、trace_id、service和caller
+// 会覆盖同名的字段
+func (w *Writer) WriteEntity(e core.Entity) error {
+	record := make(map[string]any, len(e.Fields)+5)
+	for key, val := range e.Fields {
+		record[key] = val
+	}
+	record["level"] = e.Level.String()
+	record[MessageKey] = e.Message
+	if e.TraceID != "" {
+		record["trace_id"] = e.TraceID
+	}
+	if e.Service != "" {
+		record["service"] = e.Service
+	}
+	if len(e.CE) > 0 {
+		record["caller"] = e.CE[0].String()
+	}
+
+	data := appendMapHeader(nil, len(record))
+	for key, val := range record {
+		data = appendString(data, key)
+		data = appendValue(data, val)
+	}
+
+	return w.add(time.Unix(0, e.Timestamp), data)
+}
+
+// Flush 同步发送当前的批次，开启确认时等待服务端确认后返回
+func (w *Writer) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.flush()
+}
+
+// Close 停止自动发送，发送剩余的日志后关闭连接
+func (w *Writer) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.sig)
+		w.wg.Wait()
+
+		w.lock.Lock()
+		defer w.lock.Unlock()
+
+		err = w.flush()
+		if w.conn != nil {
+			err = errors.Join(err, w.conn.Close())
+			w.conn = nil
+		}
+	})
+
+	return err
+}
+
+func (w *Writer) add(t time.Time, record []byte) error {
+	select {
+	case <-w.sig:
+		return errorx.ErrBufferClose
+	default:
+	}
+
+	entry := appendArrayHeader(make([]byte, 0, len(record)+16), 2)
+	entry = appendEventTime(entry, t)
+	entry = append(entry, record...)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.entries = append(w.entries, entry)
+	if len(w.entries) >= w.bufferSize {
+		return w.flush()
+	}
+
+	return nil
+}
+
+// flush 发送当前的批次，失败时重新建立连接重试一次，仍然失败时保留批次到下一次发送，调用方需要持有锁
+func (w *Writer) flush() error {
+	if len(w.entries) == 0 {
+		return nil
+	}
+
+	msg, chunk, err := w.encode()
+	if err != nil {
+		return err
+	}
+
+	if err = w.send(msg, chunk); err != nil {
+		w.reset()
+		if err = w.send(msg, chunk); err != nil {
+			w.reset()
+			return err
+		}
+	}
+
+	w.entries = w.entries[:0]
+	return nil
+}
+
+// encode 按照forward模式编码批次，开启确认时option中携带chunk
+func (w *Writer) encode() ([]byte, string, error) {
+	size := 0
+	for _, entry := range w.entries {
+		size += len(entry)
+	}
+
+	msg := make([]byte, 0, size+len(w.tag)+64)
+	if !w.requireAck {
+		msg = appendArrayHeader(msg, 2)
+	} else {
+		msg = appendArrayHeader(msg, 3)
+	}
+	msg = appendString(msg, w.tag)
+	msg = appendArrayHeader(msg, len(w.entries))
+	for _, entry := range w.entries {
+		msg = append(msg, entry...)
+	}
+	if !w.requireAck {
+		return msg, "", nil
+	}
+
+	id := make([]byte, chunkSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", err
+	}
+	chunk := base64.StdEncoding.EncodeToString(id)
+	msg = appendMapHeader(msg, 2)
+	msg = appendString(msg, "size")
+	msg = appendInt(msg, int64(len(w.entries)))
+	msg = appendString(msg, "chunk")
+	msg = appendString(msg, chunk)
+
+	return msg, chunk, nil
+}
+
+// send 发送消息，chunk不为空时等待服务端返回相同chunk的确认
+func (w *Writer) send(msg []byte, chunk string) error {
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.conn.SetDeadline(time.Now().Add(w.timeout)); err != nil {
+		return err
+	}
+	if _, err := w.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+
+	ack, err := readAck(w.conn)
+	if err != nil {
+		return err
+	}
+	if ack != chunk {
+		return fmt.Errorf("fluentd ack mismatch: want %s, got %s", chunk, ack)
+	}
+
+	return nil
+}
+
+func (w *Writer) connect() error {
+	conn, err := net.DialTimeout("tcp", w.addr, w.timeout)
+	if err != nil {
+		return err
+	}
+
+	w.conn = conn
+	return nil
+}
+
+// reset 关闭失效的连接，下次发送时重新建立
+func (w *Writer) reset() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
+
+// asyncFlush 定时发送批次，失败的批次保留到下一次发送，错误输出到标准错误
+func (w *Writer) asyncFlush() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.sig:
+			return
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "logx: send fluentd failed: %s\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fluentd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+// decode 解码测试中用到的MessagePack类型，EventTime解码为time.Time
+func decode(r *bufio.Reader) (any, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return int64(b), nil
+	case b >= 0xe0:
+		return int64(int8(b)), nil
+	case b&0xf0 == 0x80:
+		return decodeMap(r, int(b&0x0f))
+	case b&0xf0 == 0x90:
+		return decodeArray(r, int(b&0x0f))
+	case b&0xe0 == 0xa0:
+		return decodeString(r, int(b&0x1f))
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4:
+		n, err := readSize(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		p := make([]byte, n)
+		_, err = io.ReadFull(r, p)
+		return p, err
+	case 0xcb:
+		n, err := readSize(r, 8)
+		return math.Float64frombits(uint64(n)), err
+	case 0xcf:
+		n, err := readSize(r, 8)
+		return int64(n), err
+	case 0xd3:
+		n, err := readSize(r, 8)
+		return int64(n), err
+	case 0xd7:
+		if _, err = r.ReadByte(); err != nil {
+			return nil, err
+		}
+		sec, err := readSize(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		nsec, err := readSize(r, 4)
+		return time.Unix(int64(sec), int64(nsec)), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := readSize(r, 1<<(b-0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return decodeString(r, n)
+	case 0xdc, 0xdd:
+		n, err := readSize(r, 2<<(b-0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return decodeArray(r, n)
+	case 0xde, 0xdf:
+		n, err := readSize(r, 2<<(b-0xde))
+		if err != nil {
+			return nil, err
+		}
+		return decodeMap(r, n)
+	default:
+		return nil, fmt.Errorf("unsupported msgpack type: %#x", b)
+	}
+}
+
+func readSize(r *bufio.Reader, size int) (int, error) {
+	p := make([]byte, 8)
+	if _, err := io.ReadFull(r, p[8-size:]); err != nil {
+		return 0, err
+	}
+
+	return int(binary.BigEndian.Uint64(p)), nil
+}
+
+func decodeString(r *bufio.Reader, n int) (string, error) {
+	p := make([]byte, n)
+	_, err := io.ReadFull(r, p)
+	return string(p), err
+}
+
+func decodeArray(r *bufio.Reader, n int) ([]any, error) {
+	arr := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		val, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, val)
+	}
+
+	return arr, nil
+}
+
+func decodeMap(r *bufio.Reader, n int) (map[string]any, error) {
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		key, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		val, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(key)] = val
+	}
+
+	return m, nil
+}
+
+// mockFluentd 记录收到的forward消息，option中有chunk时返回确认
+type mockFluentd struct {
+	ln       net.Listener
+	lock     sync.Mutex
+	messages [][]any
+	conns    []net.Conn
+	// 返回错误的确认
+	badAck bool
+}
+
+func newMockFluentd(t *testing.T) *mockFluentd {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	m := &mockFluentd{ln: ln}
+	go m.serve()
+	t.Cleanup(func() {
+		_ = ln.Close()
+		m.dropConns()
+	})
+
+	return m
+}
+
+func (m *mockFluentd) addr() string {
+	return m.ln.Addr().String()
+}
+
+func (m *mockFluentd) serve() {
+	for {
+		conn, err := m.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		m.lock.Lock()
+		m.conns = append(m.conns, conn)
+		m.lock.Unlock()
+		go m.handle(conn)
+	}
+}
+
+func (m *mockFluentd) handle(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	for {
+		val, err := decode(r)
+		if err != nil {
+			return
+		}
+		msg, _ := val.([]any)
+
+		m.lock.Lock()
+		m.messages = append(m.messages, msg)
+		badAck := m.badAck
+		m.lock.Unlock()
+
+		if len(msg) < 3 {
+			continue
+		}
+		option, _ := msg[2].(map[string]any)
+		chunk, _ := option["chunk"].(string)
+		if badAck {
+			chunk = "bad"
+		}
+		ack := appendMapHeader(nil, 1)
+		ack = appendString(ack, "ack")
+		ack = appendString(ack, chunk)
+		_, _ = conn.Write(ack)
+	}
+}
+
+// dropConns 断开所有已经建立的连接，模拟Fluentd重启
+func (m *mockFluentd) dropConns() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, conn := range m.conns {
+		_ = conn.Close()
+	}
+	m.conns = nil
+}
+
+func (m *mockFluentd) received() [][]any {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([][]any(nil), m.messages...)
+}
+
+// records 返回收到的所有日志记录
+func (m *mockFluentd) records() []map[string]any {
+	var records []map[string]any
+	for _, msg := range m.received() {
+		entries, _ := msg[1].([]any)
+		for _, entry := range entries {
+			pair, _ := entry.([]any)
+			record, _ := pair[1].(map[string]any)
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+func TestNewFluentdWriter(t *testing.T) {
+	_, err := NewFluentdWriter("127.0.0.1:0", "")
+	assert.NotNil(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := ln.Addr().String()
+	assert.Nil(t, ln.Close())
+	_, err = NewFluentdWriter(addr, "app", WithFluentdTimeout(time.Second))
+	assert.NotNil(t, err)
+}
+
+func TestWriter_Write(t *testing.T) {
+	m := newMockFluentd(t)
+	w, err := NewFluentdWriter(m.addr(), "app.access",
+		WithFluentdBufferSize(2),
+		WithFluentdFlushInterval(0))
+	assert.Nil(t, err)
+	defer w.Close()
+
+	before := time.Now()
+	_, err = w.Write([]byte("first\n"))
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("second"))
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(m.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	msg := m.received()[0]
+	assert.Len(t, msg, 2)
+	assert.Equal(t, "app.access", msg[0])
+	entries, _ := msg[1].([]any)
+	assert.Len(t, entries, 2)
+	pair, _ := entries[0].([]any)
+	ts, _ := pair[0].(time.Time)
+	assert.False(t, ts.Before(before.Truncate(time.Second)))
+	assert.Equal(t, []map[string]any{
+		{MessageKey: "first"},
+		{MessageKey: "second"},
+	}, m.records())
+}
+
+func TestWriter_WriteEntity(t *testing.T) {
+	m := newMockFluentd(t)
+	w, err := NewFluentdWriter(m.addr(), "app", WithFluentdFlushInterval(0))
+	assert.Nil(t, err)
+	defer w.Close()
+
+	ts := time.Date(2025, 3, 1, 8, 0, 0, 123456789, time.UTC)
+	assert.Nil(t, w.WriteEntity(core.Entity{
+		Timestamp: ts.UnixNano(),
+		Level:     core.ErrorLevel,
+		TraceID:   "abc",
+		Service:   "order",
+		Message:   "create order failed",
+		Fields:    map[string]any{"uid": 1001, "cost": 1.5, "ok": false, "err": errors.New("timeout")},
+		CE:        []core.CallerEntity{core.NewCallerEntity("/src/order/create.go", 42)},
+	}))
+	assert.Empty(t, m.received())
+	assert.Nil(t, w.Flush())
+
+	assert.Eventually(t, func() bool {
+		return len(m.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	entries, _ := m.received()[0][1].([]any)
+	pair, _ := entries[0].([]any)
+	assert.True(t, ts.Equal(pair[0].(time.Time)))
+	assert.Equal(t, map[string]any{
+		"level":    "error",
+		MessageKey: "create order failed",
+		"trace_id": "abc",
+		"service":  "order",
+		"caller":   "order/create.go:42",
+		"uid":      int64(1001),
+		"cost":     1.5,
+		"ok":       false,
+		"err":      "timeout",
+	}, pair[1])
+}
+
+func TestWriter_RequireAck(t *testing.T) {
+	m := newMockFluentd(t)
+	w, err := NewFluentdWriter(m.addr(), "app",
+		WithFluentdFlushInterval(0),
+		WithFluentdRequireAck())
+	assert.Nil(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("acked"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Flush())
+
+	msg := m.received()[0]
+	assert.Len(t, msg, 3)
+	option, _ := msg[2].(map[string]any)
+	assert.Equal(t, int64(1), option["size"])
+	assert.NotEmpty(t, option["chunk"])
+
+	// 确认不匹配时保留批次
+	m.lock.Lock()
+	m.badAck = true
+	m.lock.Unlock()
+	_, err = w.Write([]byte("unacked"))
+	assert.Nil(t, err)
+	assert.NotNil(t, w.Flush())
+	assert.Len(t, w.(*Writer).entries, 1)
+
+	m.lock.Lock()
+	m.badAck = false
+	m.lock.Unlock()
+	assert.Nil(t, w.Flush())
+	assert.Empty(t, w.(*Writer).entries)
+}
+
+func TestWriter_Reconnect(t *testing.T) {
+	m := newMockFluentd(t)
+	w, err := NewFluentdWriter(m.addr(), "app",
+		WithFluentdFlushInterval(0),
+		WithFluentdRequireAck())
+	assert.Nil(t, err)
+	defer w.Close()
+
+	m.dropConns()
+	_, err = w.Write([]byte("after restart"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Flush())
+	assert.Equal(t, []map[string]any{{MessageKey: "after restart"}}, m.records())
+}
+
+func TestWriter_AsyncFlush(t *testing.T) {
+	m := newMockFluentd(t)
+	w, err := NewFluentdWriter(m.addr(), "app", WithFluentdFlushInterval(20*time.Millisecond))
+	assert.Nil(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("async"))
+	assert.Nil(t, err)
+	assert.Eventually(t, func() bool {
+		return len(m.records()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWriter_Close(t *testing.T) {
+	m := newMockFluentd(t)
+	w, err := NewFluentdWriter(m.addr(), "app",
+		WithFluentdFlushInterval(0),
+		WithFluentdRequireAck())
+	assert.Nil(t, err)
+
+	_, err = w.Write([]byte("last"))
+	assert.Nil(t, err)
+	assert.Nil(t, w.Close())
+	assert.Nil(t, w.Close())
+	assert.Equal(t, []map[string]any{{MessageKey: "last"}}, m.records())
+
+	_, err = w.Write([]byte("closed"))
+	assert.Equal(t, errorx.ErrBufferClose, err)
+}
+
+func TestAppendValue(t *testing.T) {
+	long := string(make([]byte, 300))
+	values := []any{
+		nil, true, "", long, []byte("bin"), -1, -100, 127, 128, uint64(math.MaxUint64),
+		float32(0.5), 2.5, time.Duration(0),
+	}
+	want := []any{
+		nil, true, "", long, []byte("bin"), int64(-1), int64(-100), int64(127), int64(128), int64(-1),
+		0.5, 2.5, "0s",
+	}
+
+	var data []byte
+	data = appendArrayHeader(data, len(values))
+	for _, val := range values {
+		data = appendValue(data, val)
+	}
+
+	got, err := decode(bufio.NewReader(bytes.NewReader(data)))
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,405 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loki 通过Grafana Loki的push API批量发送日志的写入器
+package loki
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+)
+
+const (
+	// PushPath Loki push API的路径
+	PushPath = "/loki/api/v1/push"
+	// DefaultBatchSize 默认的批次大小，达到后立即发送
+	DefaultBatchSize = 1000
+	// DefaultBatchInterval 默认的自动发送间隔
+	DefaultBatchInterval = time.Second
+	// DefaultMaxPending 默认最多保留的未发送日志条数，Loki不可用时超出的旧日志被丢弃
+	DefaultMaxPending = 100 * DefaultBatchSize
+	// DefaultMaxRetries 被限流(429)时默认的最大重试次数
+	DefaultMaxRetries = 5
+	// DefaultMinBackoff 被限流且没有Retry-After时第一次重试的间隔，之后指数增长
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff 被限流且没有Retry-After时最大的重试间隔
+	DefaultMaxBackoff = 30 * time.Second
+	// errBodyLimit 请求失败时读取的响应内容的最大长度
+	errBodyLimit = 1024
+)
+
+type LokiOption func(w *Writer)
+
+// WithLokiGzip 开启gzip压缩请求内容
+func WithLokiGzip() LokiOption {
+	return func(w *Writer) {
+		w.gzip = true
+	}
+}
+
+// WithLokiBatchSize 设置批次的大小，批次中的日志条数达到后立即发送
+func WithLokiBatchSize(size int) LokiOption {
+	return func(w *Writer) {
+		w.batchSize = size
+	}
+}
+
+// WithLokiBatchInterval 设置自动发送的间隔，小于等于0时关闭自动发送
+func WithLokiBatchInterval(d time.Duration) LokiOption {
+	return func(w *Writer) {
+		w.interval = d
+	}
+}
+
+// WithLokiMaxPending 设置最多保留的未发送日志条数，超出时丢弃最旧的日志并计数，
+// 小于批次大小时使用批次大小
+func WithLokiMaxPending(n int) LokiOption {
+	return func(w *Writer) {
+		w.maxPending = n
+	}
+}
+
+// WithLokiMaxRetries 设置被限流时的最大重试次数
+func WithLokiMaxRetries(retries int) LokiOption {
+	return func(w *Writer) {
+		w.maxRetries = retries
+	}
+}
+
+// WithLokiHTTPClient 自定义HTTP客户端，比如设置超时、TLS和认证
+func WithLokiHTTPClient(client *http.Client) LokiOption {
+	return func(w *Writer) {
+		w.client = client
+	}
+}
+
+// pushRequest Loki push API的请求格式：{"streams":[{"stream":{labels},"values":[[ns_timestamp,line]]}]}
+type pushRequest struct {
+	Streams []stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Writer Loki写入器，日志先缓存在批次中，达到批次大小、定时或者调用Flush时发送
+type Writer struct {
+	// push API的完整地址
+	pushURL string
+	// 日志流的标签
+	labels map[string]string
+	// HTTP客户端
+	client *http.Client
+	// 是否开启gzip压缩
+	gzip bool
+	// 批次的大小
+	batchSize int
+	// 自动发送的间隔
+	interval time.Duration
+	// 被限流时的最大重试次数
+	maxRetries int
+	// 被限流且没有Retry-After时的重试间隔范围
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	// 最多保留的未发送日志条数
+	maxPending int
+	// 保护当前的批次，发送请求时不持有，避免阻塞写入
+	lock sync.Mutex
+	// 保证批次按照顺序发送
+	sendLock sync.Mutex
+	// 当前的批次，格式为[纳秒时间戳, 日志内容]
+	values [][2]string
+	// 超出maxPending时丢弃的日志条数
+	dropped atomic.Int64
+	// logfmt格式化器，用于WriteEntity
+	formatter core.BinaryFormatter
+	// 关闭信号
+	sig chan struct{}
+	// 单例
+	once sync.Once
+	// 等待自动发送的goroutine退出
+	wg sync.WaitGroup
+}
+
+// NewLokiWriter 创建Loki写入器，pushURL可以是Loki的地址，也可以是包含PushPath的完整地址
+func NewLokiWriter(pushURL string, labels map[string]string, opts ...LokiOption) (core.Writer, error) {
+	u, err := url.Parse(pushURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported loki scheme: %s", u.Scheme)
+	}
+	if !strings.HasSuffix(u.Path, PushPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + PushPath
+	}
+	if len(labels) == 0 {
+		return nil, errors.New("loki stream labels can't be empty")
+	}
+
+	w := &Writer{
+		pushURL:    u.String(),
+		labels:     labels,
+		client:     http.DefaultClient,
+		batchSize:  DefaultBatchSize,
+		interval:   DefaultBatchInterval,
+		maxPending: DefaultMaxPending,
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+		formatter:  core.NewLogfmtFormatter(),
+		sig:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.batchSize < 1 {
+		w.batchSize = 1
+	}
+	w.maxPending = max(w.maxPending, w.batchSize)
+
+	if w.interval > 0 {
+		w.wg.Add(1)
+		go w.asyncFlush()
+	}
+
+	return w, nil
+}
+
+// Write 每次写入的数据作为一行日志，时间为写入的时间
+func (w *Writer) Write(p []byte) (n int, err error) {
+	if err = w.add(time.Now().UnixNano(), string(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// WriteEntity 结构化日志格式化为logfmt，Loki可以使用logfmt解析器提取字段
+func (w *Writer) WriteEntity(e core.Entity) error {
+	return w.add(e.Timestamp, string(w.formatter.Format(e)))
+}
+
+// Flush 同步发送当前的批次
+func (w *Writer) Flush() error {
+	return w.flush()
+}
+
+// Dropped 返回未发送的日志超出上限时丢弃的条数
+func (w *Writer) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close 停止自动发送，并发送剩余的日志
+func (w *Writer) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.sig)
+		w.wg.Wait()
+		err = w.Flush()
+	})
+
+	return err
+}
+
+func (w *Writer) add(ts int64, line string) error {
+	select {
+	case <-w.sig:
+		return errorx.ErrBufferClose
+	default:
+	}
+
+	w.lock.Lock()
+	w.values = append(w.values, [2]string{strconv.FormatInt(ts, 10), line})
+	w.trim()
+	full := len(w.values) >= w.batchSize
+	w.lock.Unlock()
+
+	if full {
+		return w.flush()
+	}
+
+	return nil
+}
+
+// trim 未发送的日志超出maxPending时丢弃最旧的日志，调用方需要持有lock
+func (w *Writer) trim() {
+	if n := len(w.values) - w.maxPending; n > 0 {
+		w.values = w.values[n:]
+		w.dropped.Add(int64(n))
+	}
+}
+
+// flush 取出当前的批次后发送，发送期间不持有lock，写入不会被HTTP请求和重试阻塞，
+// 发送失败的批次放回到新写入的日志之前，保留到下一次发送
+func (w *Writer) flush() error {
+	w.sendLock.Lock()
+	defer w.sendLock.Unlock()
+
+	w.lock.Lock()
+	batch := w.values
+	w.values = nil
+	w.lock.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := w.send(batch); err != nil {
+		w.lock.Lock()
+		w.values = append(batch, w.values...)
+		w.trim()
+		w.lock.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// send 发送批次，被限流时按照Retry-After或者指数退避重试
+func (w *Writer) send(batch [][2]string) error {
+	body, err := w.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := w.push(body)
+		if err == nil {
+			return nil
+		}
+		if retryAfter < 0 || attempt >= w.maxRetries {
+			return err
+		}
+
+		if retryAfter == 0 {
+			retryAfter = w.backoff(attempt)
+		}
+		time.Sleep(retryAfter)
+	}
+}
+
+// encode 序列化批次，开启gzip时压缩
+func (w *Writer) encode(batch [][2]string) ([]byte, error) {
+	data, err := json.Marshal(pushRequest{
+		Streams: []stream{{Stream: w.labels, Values: batch}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !w.gzip {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err = gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err = gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// push 发送请求，被限流时返回需要等待的时间，为0表示使用指数退避，其他的错误返回-1表示不重试
+func (w *Writer) push(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, w.pushURL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, errBodyLimit))
+	err = fmt.Errorf("loki push failed: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return -1, err
+	}
+
+	return retryAfter(resp.Header.Get("Retry-After")), err
+}
+
+// backoff 第attempt次重试(从0开始)的指数退避间隔
+func (w *Writer) backoff(attempt int) time.Duration {
+	d := w.minBackoff
+	for i := 0; i < attempt && d < w.maxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, w.maxBackoff)
+}
+
+// retryAfter 解析Retry-After，支持秒数和HTTP时间两种格式，无法解析时返回0
+func retryAfter(val string) time.Duration {
+	if val == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(val); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(val); err == nil {
+		return max(time.Until(t), 0)
+	}
+
+	return 0
+}
+
+// asyncFlush 定时发送批次，失败的批次保留到下一次发送，错误输出到标准错误
+func (w *Writer) asyncFlush() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.sig:
+			return
+		case <-ticker.C:
+			if err := w.Flush(); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "logx: push loki failed: %s\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 TimeWtr
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loki
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TimeWtr/logx/core"
+	"github.com/TimeWtr/logx/errorx"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLoki 记录收到的请求，前limited次请求返回429
+type mockLoki struct {
+	lock       sync.Mutex
+	requests   []pushRequest
+	encodings  []string
+	limited    int
+	retryAfter string
+}
+
+func (m *mockLoki) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if r.URL.Path != PushPath {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if m.limited > 0 {
+		m.limited--
+		if m.retryAfter != "" {
+			rw.Header().Set("Retry-After", m.retryAfter)
+		}
+		rw.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	var body io.Reader = r.Body
+	encoding := r.Header.Get("Content-Encoding")
+	if encoding == "gzip" {
+		gr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = gr
+	}
+
+	var req pushRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	m.requests = append(m.requests, req)
+	m.encodings = append(m.encodings, encoding)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockLoki) received() []pushRequest {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]pushRequest(nil), m.requests...)
+}
+
+func TestNewLokiWriter(t *testing.T) {
+	labels := map[string]string{"app": "api"}
+	_, err := NewLokiWriter("ftp://localhost", labels)
+	assert.Error(t, err)
+	_, err = NewLokiWriter("http://localhost", nil)
+	assert.Error(t, err)
+
+	w, err := NewLokiWriter("http://localhost:3100/", labels, WithLokiBatchInterval(0))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:3100"+PushPath, w.(*Writer).pushURL)
+	w, err = NewLokiWriter("http://localhost:3100"+PushPath, labels, WithLokiBatchInterval(0))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://localhost:3100"+PushPath, w.(*Writer).pushURL)
+}
+
+func TestWriter_Batch(t *testing.T) {
+	m := &mockLoki{}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	labels := map[string]string{"app": "api"}
+	w, err := NewLokiWriter(srv.URL, labels, WithLokiBatchSize(2), WithLokiBatchInterval(0), WithLokiGzip())
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Empty(t, m.received())
+	// 达到批次大小时立即发送
+	ts := time.Date(2025, 5, 12, 12, 12, 0, 0, time.UTC).UnixNano()
+	assert.NoError(t, w.WriteEntity(core.Entity{Timestamp: ts, Level: core.ErrorLevel, Message: "failed"}))
+
+	reqs := m.received()
+	assert.Len(t, reqs, 1)
+	assert.Equal(t, "gzip", m.encodings[0])
+	assert.Len(t, reqs[0].Streams, 1)
+	assert.Equal(t, labels, reqs[0].Streams[0].Stream)
+	values := reqs[0].Streams[0].Values
+	assert.Len(t, values, 2)
+	assert.Equal(t, "hello", values[0][1])
+	assert.Equal(t, "1747051920000000000", values[1][0])
+	assert.Contains(t, values[1][1], "level=error msg=failed")
+
+	assert.NoError(t, w.Close())
+	_, err = w.Write([]byte("hello"))
+	assert.ErrorIs(t, err, errorx.ErrBufferClose)
+}
+
+func TestWriter_RateLimited(t *testing.T) {
+	m := &mockLoki{limited: 2}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	w, err := NewLokiWriter(srv.URL, map[string]string{"app": "api"},
+		WithLokiBatchInterval(0), WithLokiMaxRetries(2))
+	assert.NoError(t, err)
+	w.(*Writer).minBackoff = time.Millisecond
+
+	// 没有Retry-After时使用指数退避
+	_, err = w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Flush())
+	assert.Len(t, m.received(), 1)
+
+	// 超过最大重试次数时返回错误，批次保留到下一次发送
+	m.limited, m.retryAfter = 3, "1"
+	start := time.Now()
+	_, err = w.Write([]byte("world"))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, w.Flush(), "429")
+	assert.GreaterOrEqual(t, time.Since(start), 2*time.Second)
+	assert.NoError(t, w.Flush())
+	assert.Len(t, m.received(), 2)
+}
+
+func TestWriter_WriteDuringPush(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		<-release
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w, err := NewLokiWriter(srv.URL, map[string]string{"app": "api"}, WithLokiBatchInterval(0))
+	assert.NoError(t, err)
+
+	_, err = w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Flush()
+	}()
+
+	// 发送请求期间写入不会被阻塞
+	assert.Eventually(t, func() bool {
+		w.(*Writer).lock.Lock()
+		defer w.(*Writer).lock.Unlock()
+		return len(w.(*Writer).values) == 0
+	}, time.Second, time.Millisecond)
+	_, err = w.Write([]byte("world"))
+	assert.NoError(t, err)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.NoError(t, w.Close())
+}
+
+func TestWriter_MaxPending(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	w, err := NewLokiWriter(srv.URL, map[string]string{"app": "api"},
+		WithLokiBatchSize(2), WithLokiBatchInterval(0), WithLokiMaxPending(3))
+	assert.NoError(t, err)
+	lw := w.(*Writer)
+
+	// 发送失败的批次保留，超出上限时丢弃最旧的日志
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		_, _ = w.Write([]byte(line))
+	}
+	assert.Equal(t, int64(2), lw.Dropped())
+	assert.Len(t, lw.values, 3)
+	assert.Equal(t, "e", lw.values[2][1])
+	assert.Error(t, w.Close())
+}
+
+func TestWriter_Interval(t *testing.T) {
+	m := &mockLoki{}
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	w, err := NewLokiWriter(srv.URL, map[string]string{"app": "api"}, WithLokiBatchInterval(10*time.Millisecond))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	_, err = w.Write([]byte("hello"))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return len(m.received()) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), retryAfter(""))
+	assert.Equal(t, time.Duration(0), retryAfter("invalid"))
+	assert.Equal(t, 3*time.Second, retryAfter("3"))
+	d := retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.Greater(t, d, 50*time.Second)
+}